@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+)
+
+// 后台任务控制器
+type JobController struct {
+	*BaseController
+	jobManager *job.Manager
+}
+
+func NewJobController(baseController *BaseController, jobManager *job.Manager) *JobController {
+	return &JobController{BaseController: baseController, jobManager: jobManager}
+}
+
+// StartJobAction 启动只读类型的后台任务，会修改数据的任务需通过各自的写接口启动
+func (this *JobController) StartJobAction(ctx *gin.Context) {
+	req := new(dto.JobStartRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("启动后台任务", "conn_id:", req.EsConnect, "database:", req.Database, "kind:", req.Kind)
+
+	if this.jobManager.IsWritable(req.Kind) {
+		this.Error(ctx, fmt.Errorf("任务类型%s会修改数据，请通过对应的接口启动", req.Kind))
+		return
+	}
+
+	j, err := this.jobManager.Start(ctx, req.Kind, req.EsConnect, req.Database, util.GetEvUserID(ctx), req.Params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动后台任务失败", "kind:", req.Kind, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// JobStatusAction 查询后台任务状态与进度，只能查询当前用户的任务
+func (this *JobController) JobStatusAction(ctx *gin.Context) {
+	req := new(dto.JobIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	record, err := this.jobManager.GetForUser(ctx, req.JobId, util.GetEvUserID(ctx))
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.SearchSuccess, toJobInfo(record))
+}
+
+// CancelJobAction 取消当前用户运行中的后台任务
+func (this *JobController) CancelJobAction(ctx *gin.Context) {
+	req := new(dto.JobIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("取消后台任务", "job_id:", req.JobId)
+
+	if err = this.jobManager.Cancel(req.JobId, util.GetEvUserID(ctx)); err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: true,
+		Message: "已发送取消请求",
+	})
+}
+
+// ResumeJobAction 从断点继续已中断、失败或取消的后台任务
+func (this *JobController) ResumeJobAction(ctx *gin.Context) {
+	req := new(dto.JobIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("继续后台任务", "job_id:", req.JobId)
+
+	j, err := this.jobManager.Resume(ctx, req.JobId, util.GetEvUserID(ctx))
+	if err != nil {
+		logger.DefaultLogger.Error("继续后台任务失败", "job_id:", req.JobId, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// JobResultAction 获取当前用户已结束的后台任务结果，失败、取消或中断的任务返回中断前保存的部分结果
+func (this *JobController) JobResultAction(ctx *gin.Context) {
+	req := new(dto.JobIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	record, err := this.jobManager.GetForUser(ctx, req.JobId, util.GetEvUserID(ctx))
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if !record.IsFinished() || (record.Status != job.StatusSucceeded && record.Result == "") {
+		this.Error(ctx, fmt.Errorf("任务状态为%s，暂无结果", record.Status))
+		return
+	}
+
+	this.Success(ctx, response.SearchSuccess, vo.JobResultResponse{
+		Job:    toJobInfo(record),
+		Result: json.RawMessage(record.Result),
+	})
+}
+
+// JobListAction 获取当前用户最近的后台任务列表
+func (this *JobController) JobListAction(ctx *gin.Context) {
+	req := new(dto.JobListRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	records, err := this.jobManager.List(ctx, req.Kind, util.GetEvUserID(ctx))
+	if err != nil {
+		logger.DefaultLogger.Error("获取后台任务列表失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	jobs := make([]vo.JobInfo, 0, len(records))
+	for _, record := range records {
+		jobs = append(jobs, toJobInfo(record))
+	}
+
+	this.Success(ctx, response.SearchSuccess, vo.JobListResponse{
+		Jobs: jobs,
+	})
+}
+
+// toJobInfo 将任务记录转换为响应VO
+func toJobInfo(record *job.Record) vo.JobInfo {
+	progress := 0.0
+	if record.Total > 0 {
+		progress = float64(record.Processed) * 100 / float64(record.Total)
+		if progress > 100 {
+			progress = 100
+		}
+	}
+	if record.Status == job.StatusSucceeded {
+		progress = 100
+	}
+
+	return vo.JobInfo{
+		JobId:      record.ID,
+		Kind:       record.Kind,
+		Status:     record.Status,
+		EsConnect:  record.EsConnect,
+		Database:   record.DbIndex,
+		Total:      record.Total,
+		Processed:  record.Processed,
+		Failed:     record.Failed,
+		Progress:   progress,
+		Message:    record.Message,
+		Error:      record.Error,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+		FinishedAt: record.FinishedAt,
+		ResumedBy:  record.ResumedBy,
+	}
+}
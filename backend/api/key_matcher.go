@@ -0,0 +1,163 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Key搜索匹配模式
+const (
+	KeyMatchContains = "contains" // 包含匹配，转换为 *text* 下推到SCAN MATCH
+	KeyMatchGlob     = "glob"     // glob匹配，原样作为SCAN MATCH
+	KeyMatchPrefix   = "prefix"   // 前缀匹配，转换为 text* 下推到SCAN MATCH
+	KeyMatchRegex    = "regex"    // Go正则匹配，在插件侧过滤
+)
+
+// buildKeyMatcher 根据匹配模式构建SCAN MATCH的pattern以及插件侧的过滤函数（无需过滤时为nil）
+func buildKeyMatcher(mode, text string, caseSensitive bool) (string, func(key string) bool, error) {
+	if text == "" {
+		return "*", nil, nil
+	}
+
+	switch mode {
+	case KeyMatchContains:
+		return globMatcher("*"+escapeGlob(text)+"*", caseSensitive)
+	case KeyMatchPrefix:
+		return globMatcher(escapeGlob(text)+"*", caseSensitive)
+	case KeyMatchGlob:
+		return globMatcher(text, caseSensitive)
+	case KeyMatchRegex:
+		expr := text
+		if !caseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return "", nil, fmt.Errorf("正则表达式不合法: %w", err)
+		}
+		return "*", re.MatchString, nil
+	default:
+		return "", nil, fmt.Errorf("不支持的匹配模式: %s", mode)
+	}
+}
+
+// escapeGlob 转义glob中的特殊字符，使文本按字面量匹配
+func escapeGlob(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// globMatcher 构建glob对应的SCAN MATCH pattern，不区分大小写且含非ASCII字母时返回插件侧的过滤函数
+func globMatcher(glob string, caseSensitive bool) (string, func(key string) bool, error) {
+	pattern, broadened := toMatchPattern(glob, caseSensitive)
+	if !broadened {
+		return pattern, nil, nil
+	}
+	re, err := globToRegexp(glob)
+	if err != nil {
+		return "", nil, err
+	}
+	return pattern, re.MatchString, nil
+}
+
+// toMatchPattern 不区分大小写时把glob中方括号外的ASCII字母改写为 [aA] 形式，SCAN MATCH本身区分大小写
+// Redis按字节匹配，多字节字符放进方括号永远匹配不上，非ASCII字母改写为*放宽匹配，broadened为true时需在插件侧再过滤
+func toMatchPattern(pattern string, caseSensitive bool) (string, bool) {
+	if caseSensitive {
+		return pattern, false
+	}
+
+	var b strings.Builder
+	inClass := false
+	escaped := false
+	broadened := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			b.WriteRune(r)
+		case r == '\\':
+			escaped = true
+			b.WriteRune(r)
+		case r == '[':
+			inClass = true
+			b.WriteRune(r)
+		case r == ']':
+			inClass = false
+			b.WriteRune(r)
+		case inClass || unicode.ToLower(r) == unicode.ToUpper(r):
+			b.WriteRune(r)
+		case r < utf8.RuneSelf:
+			b.WriteRune('[')
+			b.WriteRune(unicode.ToLower(r))
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteRune(']')
+		default:
+			broadened = true
+			b.WriteRune('*')
+		}
+	}
+	return b.String(), broadened
+}
+
+// globToRegexp 将Redis glob转换为不区分大小写的正则，用于toMatchPattern放宽匹配后在插件侧过滤
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			b.WriteByte('[')
+			if i+1 < len(runes) && runes[i+1] == '^' {
+				b.WriteByte('^')
+				i++
+			}
+			closed := false
+			for i++; i < len(runes); i++ {
+				c := runes[i]
+				if c == ']' {
+					closed = true
+					break
+				}
+				if c == '\\' && i+1 < len(runes) {
+					i++
+					c = runes[i]
+				}
+				if c == '-' {
+					b.WriteByte('-')
+				} else {
+					b.WriteString(regexp.QuoteMeta(string(c)))
+				}
+			}
+			if !closed {
+				return nil, fmt.Errorf("匹配模式不合法: 方括号未闭合")
+			}
+			b.WriteByte(']')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// keyVersionNone Key不存在时的版本号
+const keyVersionNone = "none"
+
+// keyVersionConflictError 版本不一致时脚本返回的错误信息
+const keyVersionConflictError = "EV_VERSION_CONFLICT"
+
+// streamHasGroupsError 原Key为带消费组的Stream时脚本返回的错误信息
+const streamHasGroupsError = "EV_STREAM_HAS_GROUPS"
+
+// keyVersionCheapPrefix 大Key的简化版本号前缀，只由类型、过期时间、长度与内存占用计算，不读取值
+const keyVersionCheapPrefix = "cheap:"
+
+// keyVersionFunction 计算Key版本号的Lua函数：类型、过期时间与DUMP序列化值的SHA1
+// 过期时间优先使用PEXPIRETIME（Redis 7.0+）的绝对时间，低版本只区分是否设置了过期时间，避免剩余TTL的变化导致版本号变化
+// DUMP与SHA1的耗时与Key大小成正比，MEMORY USAGE超过1MB或不可用时改为计算简化版本号，
+// 能检测到增删元素、改变长度与过期时间的修改，但检测不到长度与内存占用都不变的原地覆盖
+const keyVersionFunction = `
+local lengthCommands = {string = 'STRLEN', hash = 'HLEN', list = 'LLEN', set = 'SCARD', zset = 'ZCARD', stream = 'XLEN'}
+
+local function key_version(key)
+	local keyType = redis.call('TYPE', key)['ok']
+	if keyType == 'none' then
+		return '` + keyVersionNone + `'
+	end
+	local expire = redis.pcall('PEXPIRETIME', key)
+	if type(expire) == 'table' then
+		expire = redis.call('PTTL', key) >= 0 and 'ttl' or '-1'
+	end
+	local memory = redis.pcall('MEMORY', 'USAGE', key)
+	if type(memory) ~= 'number' or memory > 1048576 then
+		local length = 0
+		if type(memory) ~= 'number' then
+			memory = 'unknown'
+		end
+		if lengthCommands[keyType] then
+			length = redis.call(lengthCommands[keyType], key)
+		end
+		return '` + keyVersionCheapPrefix + `' .. redis.sha1hex(keyType .. ':' .. tostring(expire) .. ':' .. length .. ':' .. memory)
+	end
+	return redis.sha1hex(keyType .. ':' .. tostring(expire) .. ':' .. redis.call('DUMP', key))
+end
+`
+
+// keyVersionScript 获取Key的版本号
+const keyVersionScript = keyVersionFunction + `
+return key_version(KEYS[1])
+`
+
+// replaceKeyScript 整体替换Key：先把新值写入同slot的临时Key，全部写入成功后RENAME覆盖原Key并设置TTL
+// Redis脚本出错时不会回滚已执行的命令，所以写入阶段使用pcall，失败时删除临时Key并返回错误，原Key保持不变
+// 传入版本号时先比较原Key的当前版本，不一致则不做任何修改并返回冲突错误
+//
+// KEYS[1] 目标Key，KEYS[2] 临时Key
+// ARGV[1] 类型，ARGV[2] TTL秒数（<=0表示不过期），ARGV[3] 期望的版本号（为空表示不检查），ARGV[4...] 写入参数：
+// string为值；hash为field, value...；list/set为元素；zset为score, member...；stream为id, 字段数, field, value...
+const replaceKeyScript = keyVersionFunction + `
+local tmp = KEYS[2]
+local keyType = ARGV[1]
+local ttl = tonumber(ARGV[2])
+
+if ARGV[3] ~= '' and key_version(KEYS[1]) ~= ARGV[3] then
+	return redis.error_reply('` + keyVersionConflictError + `')
+end
+
+-- RENAME覆盖会丢弃原Stream的消费组与待确认列表，存在消费组时拒绝整体替换
+if redis.call('TYPE', KEYS[1])['ok'] == 'stream' and #redis.call('XINFO', 'GROUPS', KEYS[1]) > 0 then
+	return redis.error_reply('` + streamHasGroupsError + `')
+end
+
+local function call(...)
+	local res = redis.pcall(...)
+	if type(res) == 'table' and res.err then
+		redis.call('DEL', tmp)
+		return res
+	end
+end
+
+redis.call('DEL', tmp)
+
+local failed
+if keyType == 'string' then
+	failed = call('SET', tmp, ARGV[4])
+elseif keyType == 'stream' then
+	local i = 4
+	while not failed and i <= #ARGV do
+		local n = tonumber(ARGV[i + 1])
+		failed = call('XADD', tmp, ARGV[i], unpack(ARGV, i + 2, i + 1 + n * 2))
+		i = i + 2 + n * 2
+	end
+else
+	local command = ({hash = 'HSET', list = 'RPUSH', set = 'SADD', zset = 'ZADD'})[keyType]
+	-- 分批写入，避免unpack超出Lua栈限制；批大小为偶数，保证hash/zset的参数成对
+	for i = 4, #ARGV, 1000 do
+		failed = call(command, tmp, unpack(ARGV, i, math.min(i + 999, #ARGV)))
+		if failed then
+			break
+		end
+	end
+end
+if failed then
+	return failed
+end
+
+-- 空值不创建Key，与删除等价
+if redis.call('EXISTS', tmp) == 0 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+
+redis.call('RENAME', tmp, KEYS[1])
+if ttl > 0 then
+	redis.call('EXPIRE', KEYS[1], ttl)
+end
+return 1
+`
+
+// replaceTempKey 生成与目标Key处于同一slot的临时Key，保证集群模式下脚本涉及的Key在同一节点
+func replaceTempKey(key string) string {
+	suffix := ":ev_redis_tmp:" + randomHex(8)
+	// 已包含有效hash tag时追加后缀不改变slot
+	if _, ok := keyHashTag(key); ok {
+		return key + suffix
+	}
+	// 不含}时用目标Key整体作为hash tag，计算出的tag与原Key相同
+	if !strings.Contains(key, "}") {
+		return "{" + key + "}" + suffix
+	}
+	// 否则整体包裹后tag会在Key中的}处截断，改为查找与原Key同slot的tag
+	slot := keyHashSlot(key)
+	for i := 0; ; i++ {
+		tag := strconv.Itoa(i)
+		if crc16(tag)%clusterSlots == slot {
+			return "{" + tag + "}" + suffix
+		}
+	}
+}
+
+// clusterSlots 集群模式的slot数量
+const clusterSlots = 16384
+
+// keyHashTag 按Redis的规则提取hash tag：第一个{与其后第一个}之间的非空内容
+func keyHashTag(key string) (string, bool) {
+	start := strings.Index(key, "{")
+	if start < 0 {
+		return "", false
+	}
+	end := strings.Index(key[start+1:], "}")
+	if end <= 0 {
+		return "", false
+	}
+	return key[start+1 : start+1+end], true
+}
+
+// keyHashSlot 计算Key在集群模式下的slot
+func keyHashSlot(key string) uint16 {
+	if tag, ok := keyHashTag(key); ok {
+		key = tag
+	}
+	return crc16(key) % clusterSlots
+}
+
+// crc16 Redis集群使用的CRC16（XMODEM）
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// isStreamHasGroups 是否为原Stream存在消费组导致的保存失败
+func isStreamHasGroups(err error) bool {
+	return err != nil && strings.Contains(err.Error(), streamHasGroupsError)
+}
+
+// isKeyVersionConflict 是否为版本号不一致导致的保存失败
+func isKeyVersionConflict(err error) bool {
+	return err != nil && strings.Contains(err.Error(), keyVersionConflictError)
+}
+
+// randomHex 生成n字节的随机十六进制串
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultBigKeyBytes    = 1024 * 1024 // 默认内存阈值1MB
+	defaultBigKeyElements = 5000        // 默认元素数量阈值
+)
+
+// elementCountCommands 各类型获取元素数量的命令，string没有元素数量，只按内存阈值判定
+var elementCountCommands = map[string]string{
+	"hash":   "HLEN",
+	"list":   "LLEN",
+	"set":    "SCARD",
+	"zset":   "ZCARD",
+	"stream": "XLEN",
+}
+
+// GetBigKeysAction 大Key分析 - 按游标分页扫描，返回本页超过阈值的Key及按类型的TopN
+func (this *RedisController) GetBigKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisBigKeysRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 设置默认值
+	if req.Pattern == "" {
+		req.Pattern = "*"
+	}
+	if req.Count <= 0 {
+		req.Count = 1000
+	}
+	if req.Cursor == "" {
+		req.Cursor = "0"
+	}
+	normalizeBigKeyOptions(&req.RedisBigKeyOptions)
+
+	logger.DefaultLogger.Debug("开始Redis大Key分析",
+		"conn_id:", req.EsConnect,
+		"database:", req.Database,
+		"pattern:", req.Pattern,
+		"cursor:", req.Cursor)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	keys, nextCursor, err := this.scanKeysPage(ctx, api, req.Database, req.Cursor, req.Pattern, req.Count, nil)
+	if err != nil {
+		logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	infos, _ := this.analyzeBigKeys(ctx, api, req.Database, keys, &req.RedisBigKeyOptions)
+
+	ranking := newBigKeyRanking(req.TopN)
+	bigKeys := make([]vo.RedisBigKeyInfo, 0)
+	for _, info := range infos {
+		ranking.add(info)
+		if info.OverBytes || info.OverElements {
+			bigKeys = append(bigKeys, info)
+		}
+	}
+	sortBigKeysBySize(bigKeys)
+
+	this.Success(ctx, response.SearchSuccess, vo.RedisBigKeysResponse{
+		BigKeys:       bigKeys,
+		TypeSummaries: ranking.summaries(),
+		ScannedKeys:   len(keys),
+		NextCursor:    nextCursor,
+	})
+}
+
+// ExportBigKeysAction 将big_keys任务结果导出为CSV
+func (this *RedisController) ExportBigKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisBigKeysExportRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	record, err := this.jobManager.GetForUser(ctx, req.JobId, util.GetEvUserID(ctx))
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if record.Kind != JobKindBigKeys || record.Status != job.StatusSucceeded {
+		this.Error(ctx, fmt.Errorf("任务%s不是已完成的大Key分析任务", req.JobId))
+		return
+	}
+
+	result := new(vo.RedisBigKeysJobResult)
+	if err = json.Unmarshal([]byte(record.Result), result); err != nil {
+		this.Error(ctx, fmt.Errorf("解析任务结果失败: %w", err))
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=bigkeys_%s.csv", record.ID))
+
+	w := csv.NewWriter(ctx.Writer)
+	_ = w.Write([]string{"key", "type", "size_bytes", "elements", "over_bytes", "over_elements"})
+	for _, info := range result.BigKeys {
+		_ = w.Write([]string{
+			info.Key,
+			info.Type,
+			strconv.FormatInt(info.SizeBytes, 10),
+			strconv.FormatInt(info.Elements, 10),
+			strconv.FormatBool(info.OverBytes),
+			strconv.FormatBool(info.OverElements),
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		logger.DefaultLogger.Error("导出大Key结果失败", "job_id:", req.JobId, "error:", err)
+	}
+}
+
+// bigKeysJob 大Key分析任务 - 扫描整个keyspace，汇总按类型的TopN与超过阈值的Key
+func (this *RedisController) bigKeysJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisBigKeysJobParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if params.Pattern == "" {
+		params.Pattern = "*"
+	}
+	if params.MaxBigKeys <= 0 {
+		params.MaxBigKeys = 1000
+	}
+	normalizeBigKeyOptions(&params.RedisBigKeyOptions)
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	if dbSizeResult, err := this.executeRedisCommandWithRetry(ctx, api, j.Database, "DBSIZE"); err == nil && dbSizeResult != nil {
+		j.SetTotal(cast.ToInt64(dbSizeResult))
+	}
+
+	result := &vo.RedisBigKeysJobResult{Pattern: params.Pattern}
+	ranking := newBigKeyRanking(params.TopN)
+
+	cursor := "0"
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		nextCursor, keys, err := this.scanKeys(ctx, api, j.Database, cursor, params.Pattern, maxScanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		infos, failed := this.analyzeBigKeys(ctx, api, j.Database, keys, &params.RedisBigKeyOptions)
+		for _, info := range infos {
+			ranking.add(info)
+			if info.OverBytes || info.OverElements {
+				result.BigKeyCount++
+				result.BigKeys = append(result.BigKeys, info)
+			}
+		}
+		// 超过上限时只保留最大的部分
+		if len(result.BigKeys) > params.MaxBigKeys*2 {
+			sortBigKeysBySize(result.BigKeys)
+			result.BigKeys = result.BigKeys[:params.MaxBigKeys]
+		}
+
+		result.ScannedKeys += int64(len(keys))
+		j.AddProcessed(int64(len(keys)))
+		j.AddFailed(int64(failed))
+		j.SetMessage(fmt.Sprintf("已扫描%d个Key，发现%d个大Key", result.ScannedKeys, result.BigKeyCount))
+
+		cursor = nextCursor
+		if cursor == "0" {
+			break
+		}
+	}
+
+	sortBigKeysBySize(result.BigKeys)
+	if len(result.BigKeys) > params.MaxBigKeys {
+		result.BigKeys = result.BigKeys[:params.MaxBigKeys]
+	}
+	result.Truncated = result.BigKeyCount > int64(len(result.BigKeys))
+	result.TypeSummaries = ranking.summaries()
+
+	return result, nil
+}
+
+// analyzeBigKeys 并发获取一批key的类型、内存与元素数量，返回成功分析的结果与失败数量
+func (this *RedisController) analyzeBigKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string, options *dto.RedisBigKeyOptions) ([]vo.RedisBigKeyInfo, int) {
+	infos := make([]vo.RedisBigKeyInfo, 0, len(keys))
+	failed := 0
+	var mu sync.Mutex // 保护共享数据
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			// 优先使用MEMORY USAGE（默认采样），不支持时退化为估算
+			keyInfo, err := this.analyzeKeyMemoryOfficial(gctx, api, database, key)
+			if err != nil {
+				keyInfo, err = this.analyzeKeyMemoryFast(gctx, api, database, key)
+			}
+			if err == nil && keyInfo.Type == "unknown" {
+				err = fmt.Errorf("Key不存在")
+			}
+			var elements int64
+			if err == nil {
+				elements, err = this.keyElementCount(gctx, api, database, key, keyInfo.Type)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Debug("分析大Key失败", "key:", key, "error:", err)
+				failed++
+				return nil
+			}
+
+			info := vo.RedisBigKeyInfo{
+				Key:       key,
+				Type:      keyInfo.Type,
+				SizeBytes: keyInfo.SizeBytes,
+				Elements:  elements,
+			}
+			info.OverBytes = info.SizeBytes >= options.ThresholdBytes
+			if threshold, ok := options.ThresholdElements[info.Type]; ok {
+				info.OverElements = info.Elements >= threshold
+			}
+			infos = append(infos, info)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return infos, failed
+}
+
+// keyElementCount 获取key的元素数量，不支持的类型返回0
+func (this *RedisController) keyElementCount(ctx context.Context, api *ev_api.EvApiAdapter, database int, key, keyType string) (int64, error) {
+	command, ok := elementCountCommands[keyType]
+	if !ok {
+		return 0, nil
+	}
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, command, key)
+	if err != nil {
+		return 0, err
+	}
+	return cast.ToInt64(result), nil
+}
+
+// normalizeBigKeyOptions 填充大Key判定条件的默认值
+func normalizeBigKeyOptions(options *dto.RedisBigKeyOptions) {
+	if options.ThresholdBytes <= 0 {
+		options.ThresholdBytes = defaultBigKeyBytes
+	}
+	if options.TopN <= 0 {
+		options.TopN = 10
+	}
+	thresholds := map[string]int64{}
+	for keyType := range elementCountCommands {
+		thresholds[keyType] = defaultBigKeyElements
+	}
+	for keyType, threshold := range options.ThresholdElements {
+		if _, ok := elementCountCommands[keyType]; ok && threshold > 0 {
+			thresholds[keyType] = threshold
+		}
+	}
+	options.ThresholdElements = thresholds
+}
+
+func sortBigKeysBySize(keys []vo.RedisBigKeyInfo) {
+	sort.Slice(keys, func(i, k int) bool {
+		return keys[i].SizeBytes > keys[k].SizeBytes
+	})
+}
+
+// bigKeyRanking 按类型维护大Key统计与TopN
+type bigKeyRanking struct {
+	topN  int
+	types map[string]*vo.RedisBigKeyTypeSummary
+}
+
+func newBigKeyRanking(topN int) *bigKeyRanking {
+	return &bigKeyRanking{topN: topN, types: map[string]*vo.RedisBigKeyTypeSummary{}}
+}
+
+func (this *bigKeyRanking) add(info vo.RedisBigKeyInfo) {
+	summary, ok := this.types[info.Type]
+	if !ok {
+		summary = &vo.RedisBigKeyTypeSummary{Type: info.Type}
+		this.types[info.Type] = summary
+	}
+	summary.Count++
+	summary.TotalSize += info.SizeBytes
+	summary.TotalElements += info.Elements
+	if info.OverBytes || info.OverElements {
+		summary.BigKeys++
+	}
+	summary.TopBySize = insertTopN(summary.TopBySize, info, this.topN, func(a, b vo.RedisBigKeyInfo) bool {
+		return a.SizeBytes > b.SizeBytes
+	})
+	summary.TopByElements = insertTopN(summary.TopByElements, info, this.topN, func(a, b vo.RedisBigKeyInfo) bool {
+		return a.Elements > b.Elements
+	})
+}
+
+func (this *bigKeyRanking) summaries() []vo.RedisBigKeyTypeSummary {
+	summaries := make([]vo.RedisBigKeyTypeSummary, 0, len(this.types))
+	for _, summary := range this.types {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, k int) bool {
+		return summaries[i].TotalSize > summaries[k].TotalSize
+	})
+	return summaries
+}
+
+// insertTopN 将info插入已按less排序的top列表，保持长度不超过n
+func insertTopN(top []vo.RedisBigKeyInfo, info vo.RedisBigKeyInfo, n int, less func(a, b vo.RedisBigKeyInfo) bool) []vo.RedisBigKeyInfo {
+	idx := sort.Search(len(top), func(i int) bool {
+		return less(info, top[i])
+	})
+	if idx >= n {
+		return top
+	}
+	top = append(top, vo.RedisBigKeyInfo{})
+	copy(top[idx+1:], top[idx:])
+	top[idx] = info
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
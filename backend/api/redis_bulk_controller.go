@@ -0,0 +1,566 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	bulkPreviewMaxRounds     = 1000  // 预览时最多执行的SCAN轮数，超过后返回不完整的统计
+	defaultBulkSampleSize    = 100   // 预览默认返回的样例Key数量
+	maxBulkSampleSize        = 1000  // 预览返回的样例Key数量上限
+	defaultBulkBatchSize     = 100   // 批量操作默认每批的Key数量
+	maxBulkBatchSize         = 1000  // 批量操作每批的Key数量上限
+	defaultBulkKeysPerSecond = 1000  // 批量操作默认每秒处理的Key数量
+	maxBulkExpireKeys        = 10000 // 批量设置过期时间时一次最多指定的Key数量
+	bulkDeleteOverrunFactor  = 2     // 批量删除的匹配数量超过预览数量的倍数（再加上余量）时停止任务
+	bulkDeleteOverrunSlack   = 1000  // 批量删除匹配数量超出预览数量的余量，避免预览后新写入的少量Key导致任务停止
+)
+
+// matchAllProbes 判断匹配条件是否会匹配所有Key时使用的探测Key
+var matchAllProbes = []string{"", "0", "ev:probe:Key", "\x00\xff"}
+
+// BulkDeletePreviewAction 按模式批量删除的预览 - 只执行SCAN，返回匹配数量与样例Key
+func (this *RedisController) BulkDeletePreviewAction(ctx *gin.Context) {
+	req := new(dto.RedisBulkDeletePreviewRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	pattern, filter, err := buildPatternMatcher(&req.RedisKeyPatternOptions)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.SampleSize <= 0 {
+		req.SampleSize = defaultBulkSampleSize
+	}
+	if req.SampleSize > maxBulkSampleSize {
+		req.SampleSize = maxBulkSampleSize
+	}
+
+	logger.DefaultLogger.Debug("批量删除预览", "conn_id:", req.EsConnect, "database:", req.Database, "pattern:", pattern)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	resp := vo.RedisBulkDeletePreviewResponse{
+		Pattern: pattern,
+		Sample:  []string{},
+	}
+
+	cursor := "0"
+	for round := 0; round < bulkPreviewMaxRounds; round++ {
+		nextCursor, keys, err := this.scanKeys(ctx, api, req.Database, cursor, pattern, maxScanCount)
+		if err != nil {
+			logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+			this.Error(ctx, err)
+			return
+		}
+
+		resp.ScannedCount += int64(len(keys))
+		for _, key := range keys {
+			if filter != nil && !filter(key) {
+				continue
+			}
+			resp.MatchedCount++
+			if len(resp.Sample) < req.SampleSize {
+				resp.Sample = append(resp.Sample, key)
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == "0" {
+			resp.Complete = true
+			break
+		}
+	}
+
+	this.Success(ctx, response.SearchSuccess, resp)
+}
+
+// BulkDeleteAction 按模式批量删除 - 以后台任务的方式分批UNLINK，可通过任务接口查看进度与取消
+func (this *RedisController) BulkDeleteAction(ctx *gin.Context) {
+	req := new(dto.RedisBulkDeleteRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 提前校验匹配条件，避免启动注定失败的任务
+	if err := checkBulkDeletePattern(&req.RedisBulkDeleteParams); err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	params, err := json.Marshal(req.RedisBulkDeleteParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("启动批量删除任务", "conn_id:", req.EsConnect, "database:", req.Database,
+		"pattern:", req.Pattern, "match_mode:", req.MatchMode)
+
+	j, err := this.jobManager.Start(ctx, JobKindBulkDelete, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动批量删除任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// bulkDeleteJob 按模式批量删除任务 - 每轮SCAN匹配到的Key分批UNLINK并限速
+func (this *RedisController) bulkDeleteJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisBulkDeleteParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if err := checkBulkDeletePattern(params); err != nil {
+		return nil, err
+	}
+	pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, keysPerSecond := normalizeBulkRate(params.BatchSize, params.KeysPerSecond)
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	if params.Expected > 0 {
+		j.SetTotal(params.Expected)
+	}
+
+	// 续跑时在原任务中断前的结果上继续累计
+	result := &vo.RedisBulkDeleteJobResult{}
+	if err := j.BindResult(result); err != nil {
+		return nil, err
+	}
+	result.Pattern = pattern
+	j.TrackResult(result)
+	useDel := false
+
+	scanned, _, err := this.scanMatchedBatches(ctx, j, api, pattern, filter, batchSize, keysPerSecond, func(batch []string) error {
+		// 匹配数量远超预览时说明keyspace或匹配条件与预览时不同，停止任务避免误删
+		if limit := bulkDeleteOverrunLimit(params.Expected); limit > 0 && result.Matched+int64(len(batch)) > limit {
+			return fmt.Errorf("匹配的Key数量已超过%d个，远多于预览时的%d个，任务已停止，请重新预览后再删除", limit, params.Expected)
+		}
+		deleted, failed := this.unlinkKeys(ctx, api, j.Database, batch, &useDel)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result.Matched += int64(len(batch))
+		result.Deleted += deleted
+		result.Failed += failed
+		j.AddFailed(failed)
+		j.SetMessage(fmt.Sprintf("已删除%d个Key", result.Deleted))
+		return nil
+	})
+	result.Scanned += scanned
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// unlinkKeys 并发逐个UNLINK一批Key，返回删除与删除失败的Key数量
+// 逐个Key执行而不是一次UNLINK多个Key，避免集群模式下多个Key不在同一slot；Redis 4.0之前没有UNLINK，退化为DEL并记录在useDel中
+func (this *RedisController) unlinkKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string, useDel *bool) (int64, int64) {
+	var deleted, failed int64
+	var mu sync.Mutex // 保护计数器与useDel
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			mu.Lock()
+			command := "UNLINK"
+			if *useDel {
+				command = "DEL"
+			}
+			mu.Unlock()
+
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, command, key)
+			if err != nil && command == "UNLINK" && strings.Contains(strings.ToLower(err.Error()), "unknown command") {
+				mu.Lock()
+				*useDel = true
+				mu.Unlock()
+				result, err = this.executeRedisCommandWithRetry(gctx, api, database, "DEL", key)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Debug("删除Key失败", "key:", key, "command:", command, "error:", err)
+				failed++
+				return nil
+			}
+			deleted += cast.ToInt64(result)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return deleted, failed
+}
+
+// BulkExpireAction 批量设置过期时间 (EXPIRE/PEXPIRE/EXPIREAT/PERSIST)
+// 指定Key列表时同步执行并返回结果，按模式执行时启动后台任务
+func (this *RedisController) BulkExpireAction(ctx *gin.Context) {
+	req := new(dto.RedisBulkExpireRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if err := normalizeExpireParams(&req.RedisBulkExpireParams); err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if len(req.Keys) > maxBulkExpireKeys {
+		this.Error(ctx, fmt.Errorf("一次最多指定%d个Key，更多的Key请按模式执行", maxBulkExpireKeys))
+		return
+	}
+	if len(req.Keys) == 0 {
+		if _, _, err := buildPatternMatcher(&req.RedisKeyPatternOptions); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+	}
+
+	logger.DefaultLogger.Debug("批量设置过期时间", "conn_id:", req.EsConnect, "database:", req.Database,
+		"command:", req.Command, "condition:", req.Condition, "keys:", len(req.Keys), "pattern:", req.Pattern)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	// NX/XX/GT/LT在低版本上是语法错误，提前检查避免每个Key都失败
+	if req.Condition != "" {
+		version, err := this.serverVersion(ctx, api, req.Database)
+		if err != nil {
+			logger.DefaultLogger.Warn("获取Redis版本失败", "error:", err)
+		} else if !versionAtLeast(version, 7, 0) {
+			this.Error(ctx, fmt.Errorf("%s条件需要Redis 7.0+，当前版本为%s", req.Condition, version))
+			return
+		}
+	}
+
+	if len(req.Keys) > 0 {
+		result := &vo.RedisBulkExpireResult{Command: req.Command}
+		_, keysPerSecond := normalizeBulkRate(req.BatchSize, req.KeysPerSecond)
+		for start := 0; start < len(req.Keys); start += maxBulkBatchSize {
+			end := start + maxBulkBatchSize
+			if end > len(req.Keys) {
+				end = len(req.Keys)
+			}
+			changed, failed := this.applyExpire(ctx, api, req.Database, req.Keys[start:end], &req.RedisBulkExpireParams)
+			result.Matched += int64(end - start)
+			result.Changed += changed
+			result.Failed += failed
+			if end == len(req.Keys) {
+				break
+			}
+			if err := throttle(ctx, end-start, keysPerSecond); err != nil {
+				this.Error(ctx, err)
+				return
+			}
+		}
+
+		this.Success(ctx, response.OperateSuccess, vo.RedisBulkExpireResponse{Result: result})
+		return
+	}
+
+	params, err := json.Marshal(req.RedisBulkExpireParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	j, err := this.jobManager.Start(ctx, JobKindBulkExpire, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动批量设置过期时间任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	jobInfo := toJobInfo(j.Record())
+	this.Success(ctx, response.OperateSuccess, vo.RedisBulkExpireResponse{Job: &jobInfo})
+}
+
+// bulkExpireJob 按模式批量设置过期时间任务
+func (this *RedisController) bulkExpireJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisBulkExpireParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if err := normalizeExpireParams(params); err != nil {
+		return nil, err
+	}
+	pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, keysPerSecond := normalizeBulkRate(params.BatchSize, params.KeysPerSecond)
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	if params.Expected > 0 {
+		j.SetTotal(params.Expected)
+	}
+
+	// 续跑时在原任务中断前的结果上继续累计
+	result := &vo.RedisBulkExpireResult{}
+	if err := j.BindResult(result); err != nil {
+		return nil, err
+	}
+	result.Command = params.Command
+	j.TrackResult(result)
+
+	scanned, _, err := this.scanMatchedBatches(ctx, j, api, pattern, filter, batchSize, keysPerSecond, func(batch []string) error {
+		changed, failed := this.applyExpire(ctx, api, j.Database, batch, params)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result.Matched += int64(len(batch))
+		result.Changed += changed
+		result.Failed += failed
+		j.AddFailed(failed)
+		j.SetMessage(fmt.Sprintf("已修改%d个Key的过期时间", result.Changed))
+		return nil
+	})
+	result.Scanned += scanned
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyExpire 并发对一批Key执行过期命令，返回过期时间发生变化与执行失败的Key数量
+// 逐个Key执行而不是用Lua脚本批量执行，避免集群模式下多个Key不在同一slot
+func (this *RedisController) applyExpire(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string, params *dto.RedisBulkExpireParams) (int64, int64) {
+	var changed, failed int64
+	var mu sync.Mutex // 保护计数器
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			args := []interface{}{key}
+			if params.Command != "PERSIST" {
+				args = append(args, strconv.FormatInt(params.Value, 10))
+				if params.Condition != "" {
+					args = append(args, params.Condition)
+				}
+			}
+
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, params.Command, args...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Debug("设置过期时间失败", "key:", key, "command:", params.Command, "error:", err)
+				failed++
+				return nil
+			}
+			changed += cast.ToInt64(result)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return changed, failed
+}
+
+// normalizeExpireParams 校验并规范化过期命令参数
+func normalizeExpireParams(params *dto.RedisBulkExpireParams) error {
+	params.Command = strings.ToUpper(params.Command)
+	params.Condition = strings.ToUpper(params.Condition)
+
+	switch params.Command {
+	case "PERSIST":
+		if params.Condition != "" {
+			return fmt.Errorf("PERSIST不支持%s条件", params.Condition)
+		}
+	case "EXPIRE", "PEXPIRE":
+		// 过期时间为0或负数会直接删除Key，批量操作中不允许
+		if params.Value <= 0 {
+			return fmt.Errorf("%s的值必须大于0", params.Command)
+		}
+	case "EXPIREAT":
+		// 过去的时间戳同样会直接删除Key
+		if params.Value <= time.Now().Unix() {
+			return fmt.Errorf("EXPIREAT的时间戳必须晚于当前时间")
+		}
+	default:
+		return fmt.Errorf("不支持的过期命令: %s", params.Command)
+	}
+
+	switch params.Condition {
+	case "", "NX", "XX", "GT", "LT":
+		return nil
+	default:
+		return fmt.Errorf("不支持的生效条件: %s", params.Condition)
+	}
+}
+
+// scanMatchedBatches 从任务断点开始SCAN，每轮匹配到的Key按批交给handle处理并限速，整轮处理完成后才推进断点
+// handle返回错误时任务终止；返回扫描与匹配的Key数量
+func (this *RedisController) scanMatchedBatches(ctx context.Context, j *job.Job, api *ev_api.EvApiAdapter, pattern string, filter func(key string) bool,
+	batchSize, keysPerSecond int, handle func(batch []string) error) (int64, int64, error) {
+	var scanned, matched int64
+
+	cursor := j.Checkpoint()
+	if cursor == "" {
+		cursor = "0"
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return scanned, matched, err
+		}
+
+		nextCursor, keys, err := this.scanKeys(ctx, api, j.Database, cursor, pattern, maxScanCount)
+		if err != nil {
+			return scanned, matched, err
+		}
+		scanned += int64(len(keys))
+
+		var matchedKeys []string
+		for _, key := range keys {
+			if filter == nil || filter(key) {
+				matchedKeys = append(matchedKeys, key)
+			}
+		}
+		matched += int64(len(matchedKeys))
+
+		for start := 0; start < len(matchedKeys); start += batchSize {
+			end := start + batchSize
+			if end > len(matchedKeys) {
+				end = len(matchedKeys)
+			}
+
+			if err := handle(matchedKeys[start:end]); err != nil {
+				return scanned, matched, err
+			}
+			j.AddProcessed(int64(end - start))
+
+			if err := throttle(ctx, end-start, keysPerSecond); err != nil {
+				return scanned, matched, err
+			}
+		}
+
+		j.SetCheckpoint(nextCursor)
+
+		cursor = nextCursor
+		if cursor == "0" {
+			break
+		}
+	}
+
+	return scanned, matched, nil
+}
+
+// buildPatternMatcher 根据批量操作的匹配条件构建SCAN MATCH的pattern与过滤函数，默认使用glob模式
+// 批量操作影响面大，匹配文本不允许为空
+func buildPatternMatcher(options *dto.RedisKeyPatternOptions) (string, func(key string) bool, error) {
+	if options.Pattern == "" {
+		return "", nil, fmt.Errorf("匹配模式不能为空")
+	}
+	mode := options.MatchMode
+	if mode == "" {
+		mode = KeyMatchGlob
+	}
+	return buildKeyMatcher(mode, options.Pattern, options.CaseSensitive)
+}
+
+// checkBulkDeletePattern 校验批量删除的匹配条件，会匹配所有Key的条件（如glob的*、正则的.*）需要显式确认
+func checkBulkDeletePattern(params *dto.RedisBulkDeleteParams) error {
+	pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+	if err != nil {
+		return err
+	}
+	if !params.ConfirmAll && matchesAllKeys(pattern, filter) {
+		return fmt.Errorf("匹配模式 %s 会删除所有Key，如确需清空请显式确认", params.Pattern)
+	}
+	return nil
+}
+
+// matchesAllKeys 判断SCAN MATCH的pattern与过滤函数是否会匹配所有Key：pattern只由*组成，且过滤函数接受所有探测Key
+func matchesAllKeys(pattern string, filter func(key string) bool) bool {
+	if strings.Trim(pattern, "*") != "" {
+		return false
+	}
+	if filter == nil {
+		return true
+	}
+	for _, probe := range matchAllProbes {
+		if !filter(probe) {
+			return false
+		}
+	}
+	return true
+}
+
+// bulkDeleteOverrunLimit 根据预览的匹配数量计算批量删除允许的最大匹配数量，未传预览数量时返回0表示不限制
+func bulkDeleteOverrunLimit(expected int64) int64 {
+	if expected <= 0 {
+		return 0
+	}
+	return expected*bulkDeleteOverrunFactor + bulkDeleteOverrunSlack
+}
+
+// normalizeBulkRate 填充批量操作的批大小与限速默认值
+func normalizeBulkRate(batchSize, keysPerSecond int) (int, int) {
+	if batchSize <= 0 {
+		batchSize = defaultBulkBatchSize
+	}
+	if batchSize > maxBulkBatchSize {
+		batchSize = maxBulkBatchSize
+	}
+	if keysPerSecond <= 0 {
+		keysPerSecond = defaultBulkKeysPerSecond
+	}
+	return batchSize, keysPerSecond
+}
+
+// throttle 按每秒处理数量限速，处理n个Key后等待对应的时间，任务取消时立即返回
+func throttle(ctx context.Context, n, keysPerSecond int) error {
+	wait := time.Duration(n) * time.Second / time.Duration(keysPerSecond)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+const maxClientPauseMillis = 300000 // CLIENT PAUSE的最长时间，避免误操作长时间阻塞业务
+
+// CLIENT PAUSE模式
+const (
+	ClientPauseAll   = "all"   // 暂停所有客户端的命令（包括本插件）
+	ClientPauseWrite = "write" // 只暂停写命令（6.2+）
+)
+
+// 客户端类型过滤
+var clientListTypes = map[string]bool{
+	"normal": true, "master": true, "replica": true, "slave": true, "pubsub": true,
+}
+
+// GetClientListAction 获取已连接的客户端 - 解析CLIENT LIST，并按客户端名称与来源IP分组
+func (this *RedisController) GetClientListAction(ctx *gin.Context) {
+	req := new(dto.RedisClientListRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	args := []interface{}{"CLIENT", "LIST"}
+	if req.Type != "" {
+		if !clientListTypes[strings.ToLower(req.Type)] {
+			this.Error(ctx, fmt.Errorf("不支持的客户端类型: %s", req.Type))
+			return
+		}
+		args = append(args, "TYPE", strings.ToLower(req.Type))
+	}
+
+	logger.DefaultLogger.Debug("查询Redis客户端列表", "conn_id:", req.EsConnect, "type:", req.Type)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("CLIENT LIST执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	clients := parseClientList(cast.ToString(result))
+	sort.Slice(clients, func(i, k int) bool {
+		return clients[i].Idle > clients[k].Idle
+	})
+
+	this.Success(ctx, response.SearchSuccess, vo.RedisClientListResponse{
+		Clients: clients,
+		Total:   len(clients),
+		ByName:  groupClients(clients, func(client vo.RedisClientInfo) string { return client.Name }),
+		ByIp:    groupClients(clients, func(client vo.RedisClientInfo) string { return client.Ip }),
+	})
+}
+
+// KillClientAction 断开客户端连接 - CLIENT KILL，按id、addr或user过滤，默认不断开执行命令的连接
+func (this *RedisController) KillClientAction(ctx *gin.Context) {
+	req := new(dto.RedisClientKillRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	args := []interface{}{"CLIENT", "KILL"}
+	filters := 0
+	if req.Id > 0 {
+		args = append(args, "ID", req.Id)
+		filters++
+	}
+	if req.Addr != "" {
+		args = append(args, "ADDR", req.Addr)
+		filters++
+	}
+	if req.User != "" {
+		args = append(args, "USER", req.User)
+		filters++
+	}
+	if filters != 1 {
+		this.Error(ctx, fmt.Errorf("请指定id、addr、user中的一个"))
+		return
+	}
+	args = append(args, "SKIPME", "yes")
+
+	logger.DefaultLogger.Info("断开Redis客户端连接", "conn_id:", req.EsConnect,
+		"id:", req.Id, "addr:", req.Addr, "user:", req.User)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("CLIENT KILL执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	killed := cast.ToInt64(result)
+	if killed == 0 {
+		this.Error(ctx, fmt.Errorf("没有匹配的客户端连接"))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisClientKillResponse{Killed: killed})
+}
+
+// PauseClientsAction 暂停客户端 - CLIENT PAUSE，默认只暂停写命令
+// all模式下本插件的命令同样会被暂停，UNPAUSE需等待超时后才能执行，误操作无法撤销，因此只在明确指定时使用
+func (this *RedisController) PauseClientsAction(ctx *gin.Context) {
+	req := new(dto.RedisClientPauseRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.TimeoutMillis <= 0 || req.TimeoutMillis > maxClientPauseMillis {
+		this.Error(ctx, fmt.Errorf("暂停时间必须在1到%d毫秒之间", maxClientPauseMillis))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = ClientPauseWrite
+	}
+	req.Mode = strings.ToLower(req.Mode)
+
+	args := []interface{}{"CLIENT", "PAUSE", req.TimeoutMillis}
+	switch req.Mode {
+	case ClientPauseAll:
+	case ClientPauseWrite:
+		args = append(args, "WRITE")
+	default:
+		this.Error(ctx, fmt.Errorf("不支持的暂停模式: %s", req.Mode))
+		return
+	}
+
+	logger.DefaultLogger.Info("暂停Redis客户端", "conn_id:", req.EsConnect, "timeout:", req.TimeoutMillis, "mode:", req.Mode)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	if _, err = api.RedisExecCommand(ctx, req.Database, args...); err != nil {
+		logger.DefaultLogger.Error("CLIENT PAUSE执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: true,
+		Message: fmt.Sprintf("客户端已暂停%d毫秒", req.TimeoutMillis),
+	})
+}
+
+// UnpauseClientsAction 恢复客户端 - CLIENT UNPAUSE（6.2+）
+func (this *RedisController) UnpauseClientsAction(ctx *gin.Context) {
+	req := new(dto.RedisInfoRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Info("恢复Redis客户端", "conn_id:", req.EsConnect)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	if _, err = api.RedisExecCommand(ctx, req.Database, "CLIENT", "UNPAUSE"); err != nil {
+		logger.DefaultLogger.Error("CLIENT UNPAUSE执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: true,
+		Message: "客户端已恢复",
+	})
+}
+
+// parseClientList 解析CLIENT LIST的输出，每行一个客户端，字段为空格分隔的key=value
+func parseClientList(output string) []vo.RedisClientInfo {
+	clients := []vo.RedisClientInfo{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := map[string]string{}
+		for _, field := range strings.Fields(line) {
+			if key, value, ok := strings.Cut(field, "="); ok {
+				fields[key] = value
+			}
+		}
+
+		client := vo.RedisClientInfo{
+			Id:      cast.ToInt64(fields["id"]),
+			Addr:    fields["addr"],
+			Laddr:   fields["laddr"],
+			Name:    fields["name"],
+			Age:     cast.ToInt64(fields["age"]),
+			Idle:    cast.ToInt64(fields["idle"]),
+			Db:      cast.ToInt(fields["db"]),
+			Cmd:     fields["cmd"],
+			Flags:   fields["flags"],
+			Omem:    cast.ToInt64(fields["omem"]),
+			TotMem:  cast.ToInt64(fields["tot-mem"]),
+			Qbuf:    cast.ToInt64(fields["qbuf"]),
+			Sub:     cast.ToInt(fields["sub"]),
+			Psub:    cast.ToInt(fields["psub"]),
+			Multi:   cast.ToInt(fields["multi"]),
+			User:    fields["user"],
+			Resp:    cast.ToInt(fields["resp"]),
+			LibName: fields["lib-name"],
+			LibVer:  fields["lib-ver"],
+		}
+		if host, _, err := net.SplitHostPort(client.Addr); err == nil {
+			client.Ip = host
+		} else {
+			client.Ip = client.Addr // unix socket连接
+		}
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// groupClients 按key分组统计客户端，按连接数倒序
+func groupClients(clients []vo.RedisClientInfo, key func(client vo.RedisClientInfo) string) []vo.RedisClientGroup {
+	groups := map[string]*vo.RedisClientGroup{}
+	totalIdle := map[string]int64{}
+	for _, client := range clients {
+		name := key(client)
+		group, ok := groups[name]
+		if !ok {
+			group = &vo.RedisClientGroup{Key: name, Commands: map[string]int64{}}
+			groups[name] = group
+		}
+		group.Count++
+		totalIdle[name] += client.Idle
+		group.TotalOmem += client.Omem
+		group.TotalMem += client.TotMem
+		if client.Idle > group.MaxIdle {
+			group.MaxIdle = client.Idle
+		}
+		if client.Age > group.MaxAge {
+			group.MaxAge = client.Age
+		}
+		if client.Cmd != "" {
+			group.Commands[client.Cmd]++
+		}
+	}
+
+	result := make([]vo.RedisClientGroup, 0, len(groups))
+	for name, group := range groups {
+		group.AvgIdle = totalIdle[name] / group.Count
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, k int) bool {
+		if result[i].Count != result[k].Count {
+			return result[i].Count > result[k].Count
+		}
+		return result[i].Key < result[k].Key
+	})
+	return result
+}
@@ -0,0 +1,467 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+// 命令文件格式
+const (
+	CommandFormatAuto   = "auto"   // 以*开头按RESP解析，否则按命令行解析
+	CommandFormatResp   = "resp"   // RESP协议（redis-cli --pipe的输入格式）
+	CommandFormatInline = "inline" // 每行一条redis-cli命令，支持单双引号
+)
+
+const (
+	commandImportBatchSize = 1000 // 每批执行的命令数量
+	maxCommandErrorGroups  = 100  // 错误汇总中保留的错误种类数量上限
+)
+
+// importAllowedCommands 命令文件导入允许执行的命令，只包含写入数据的命令
+// 不允许SELECT（库由请求指定）以及FLUSHDB、CONFIG、EVAL等影响整个实例或无法确认作用范围的命令
+var importAllowedCommands = map[string]bool{
+	"set": true, "setex": true, "psetex": true, "setnx": true, "mset": true, "msetnx": true,
+	"append": true, "setrange": true, "setbit": true, "incr": true, "incrby": true, "incrbyfloat": true,
+	"decr": true, "decrby": true, "hset": true, "hmset": true, "hsetnx": true, "hincrby": true,
+	"hincrbyfloat": true, "hdel": true, "lpush": true, "rpush": true, "lpushx": true, "rpushx": true,
+	"lset": true, "linsert": true, "lrem": true, "ltrim": true, "sadd": true, "srem": true,
+	"zadd": true, "zincrby": true, "zrem": true, "xadd": true, "pfadd": true, "geoadd": true,
+	"expire": true, "pexpire": true, "expireat": true, "pexpireat": true, "persist": true,
+	"del": true, "unlink": true,
+}
+
+// importCommand 解析后的一条命令
+type importCommand struct {
+	line int // inline格式为行号，RESP格式为命令序号，均从1开始
+	args []string
+}
+
+// ImportCommandsAction 导入RESP或redis-cli命令文件 - 先完整解析并检查命令白名单，全部合法时启动后台任务分批执行
+func (this *RedisController) ImportCommandsAction(ctx *gin.Context) {
+	req := new(dto.RedisCommandImportRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if strings.TrimSpace(req.Content) == "" {
+		this.Error(ctx, fmt.Errorf("导入内容不能为空"))
+		return
+	}
+	if len(req.Content) > maxImportBytes {
+		this.Error(ctx, fmt.Errorf("导入内容不能超过%dMB", maxImportBytes/1024/1024))
+		return
+	}
+
+	commands, err := parseImportCommands(req.Content, &req.RedisCommandImportParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if len(commands) == 0 {
+		this.Error(ctx, fmt.Errorf("未解析到任何命令"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("导入Redis命令文件", "conn_id:", req.EsConnect, "database:", req.Database,
+		"format:", req.Format, "commands:", len(commands))
+
+	req.ContentId = randomHex(16)
+	params, err := json.Marshal(req.RedisCommandImportParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	importContents.Store(req.ContentId, req.Content)
+	j, err := this.jobManager.Start(ctx, JobKindCommandImport, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		importContents.Delete(req.ContentId)
+		logger.DefaultLogger.Error("启动命令导入任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// commandImportJob 命令文件导入任务
+func (this *RedisController) commandImportJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisCommandImportParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+
+	content, ok := importContents.LoadAndDelete(params.ContentId)
+	if !ok {
+		return nil, fmt.Errorf("导入内容已失效，插件重启后需要重新上传")
+	}
+	commands, err := parseImportCommands(content.(string), params)
+	if err != nil {
+		return nil, err
+	}
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	j.SetTotal(int64(len(commands)))
+	result := &vo.RedisCommandImportResult{Format: params.Format, Commands: int64(len(commands))}
+	errorGroups := map[string]*vo.RedisCommandErrorGroup{}
+	var mu sync.Mutex // 保护result与errorGroups
+
+	for start := 0; start < len(commands); start += commandImportBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start + commandImportBatchSize
+		if end > len(commands) {
+			end = len(commands)
+		}
+
+		this.execCommandBatch(ctx, api, j.Database, commands[start:end], func(command importCommand, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				result.Succeeded++
+				return
+			}
+			result.Failed++
+			j.AddFailed(1)
+
+			name := strings.ToUpper(command.args[0])
+			groupKey := name + "\x00" + err.Error()
+			group, ok := errorGroups[groupKey]
+			if !ok {
+				if len(errorGroups) >= maxCommandErrorGroups {
+					result.ErrorsTruncated = true
+					return
+				}
+				group = &vo.RedisCommandErrorGroup{Command: name, Error: err.Error(), FirstLine: command.line}
+				errorGroups[groupKey] = group
+			}
+			group.Count++
+			if command.line < group.FirstLine {
+				group.FirstLine = command.line
+			}
+		})
+
+		j.AddProcessed(int64(end - start))
+		j.SetMessage(fmt.Sprintf("已执行%d条命令，失败%d条", end, result.Failed))
+	}
+
+	result.ErrorSummary = make([]vo.RedisCommandErrorGroup, 0, len(errorGroups))
+	for _, group := range errorGroups {
+		result.ErrorSummary = append(result.ErrorSummary, *group)
+	}
+	sort.Slice(result.ErrorSummary, func(i, k int) bool {
+		return result.ErrorSummary[i].Count > result.ErrorSummary[k].Count
+	})
+
+	return result, nil
+}
+
+// execCommandBatch 执行一批命令。基座接口每次只能发送一条命令，这里按key分道并发执行：
+// 同一个key的命令在同一道内按文件顺序执行，涉及多个key或不含key的命令作为屏障，等之前的命令全部完成后单独执行
+// 命令可能不是幂等的（如INCR、RPUSH），不使用带重试的执行方式
+func (this *RedisController) execCommandBatch(ctx context.Context, api *ev_api.EvApiAdapter, database int, commands []importCommand, done func(command importCommand, err error)) {
+	exec := func(command importCommand) {
+		args := make([]interface{}, len(command.args))
+		for i, arg := range command.args {
+			args[i] = arg
+		}
+		_, err := api.RedisExecCommand(ctx, database, args...)
+		done(command, err)
+	}
+
+	lanes := map[string][]importCommand{}
+	var order []string
+	runLanes := func() {
+		g, _ := errgroup.WithContext(ctx)
+		g.SetLimit(memoryAnalysisConcurrency)
+		for _, key := range order {
+			lane := lanes[key]
+			g.Go(func() error {
+				for _, command := range lane {
+					exec(command)
+				}
+				return nil
+			})
+		}
+		_ = g.Wait()
+		lanes = map[string][]importCommand{}
+		order = nil
+	}
+
+	for _, command := range commands {
+		keys := monitorCommandKeys(strings.ToLower(command.args[0]), command.args[1:])
+		if len(keys) != 1 {
+			runLanes()
+			exec(command)
+			continue
+		}
+		if _, ok := lanes[keys[0]]; !ok {
+			order = append(order, keys[0])
+		}
+		lanes[keys[0]] = append(lanes[keys[0]], command)
+	}
+	runLanes()
+}
+
+// parseImportCommands 按格式解析命令文件并检查命令白名单，有任何不合法的命令时返回错误，不执行任何命令
+func parseImportCommands(content string, params *dto.RedisCommandImportParams) ([]importCommand, error) {
+	params.Format = strings.ToLower(params.Format)
+	if params.Format == "" || params.Format == CommandFormatAuto {
+		params.Format = CommandFormatInline
+		if strings.HasPrefix(strings.TrimLeft(content, " \t\r\n"), "*") {
+			params.Format = CommandFormatResp
+		}
+	}
+
+	var commands []importCommand
+	var err error
+	switch params.Format {
+	case CommandFormatResp:
+		commands, err = parseRespCommands(content)
+	case CommandFormatInline:
+		commands, err = parseInlineCommands(content)
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", params.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := importAllowedCommands
+	if len(params.AllowedCommands) > 0 {
+		// 请求中的白名单只能进一步收窄内置白名单
+		allowed = map[string]bool{}
+		for _, name := range params.AllowedCommands {
+			name = strings.ToLower(name)
+			if importAllowedCommands[name] {
+				allowed[name] = true
+			}
+		}
+	}
+
+	position := "第%d行: %s"
+	if params.Format == CommandFormatResp {
+		position = "第%d条: %s"
+	}
+	var disallowed []string
+	for _, command := range commands {
+		if name := strings.ToLower(command.args[0]); !allowed[name] {
+			disallowed = append(disallowed, fmt.Sprintf(position, command.line, strings.ToUpper(name)))
+			if len(disallowed) >= 10 {
+				break
+			}
+		}
+	}
+	if len(disallowed) > 0 {
+		return nil, fmt.Errorf("存在不允许执行的命令，未执行任何命令: %s", strings.Join(disallowed, "; "))
+	}
+	return commands, nil
+}
+
+// parseRespCommands 解析RESP格式：每条命令为*<参数个数>\r\n后跟若干$<长度>\r\n<内容>\r\n
+// 参数个数与长度来自文件内容，分配内存前先按剩余内容的大小校验，避免构造的超大值导致内存耗尽
+func parseRespCommands(content string) ([]importCommand, error) {
+	source := strings.NewReader(content)
+	reader := bufio.NewReader(source)
+	var commands []importCommand
+
+	// remaining 尚未读取的字节数
+	remaining := func() int {
+		return source.Len() + reader.Buffered()
+	}
+
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	for index := 1; ; index++ {
+		header, err := readLine()
+		if err == io.EOF {
+			return commands, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(header) == "" {
+			index--
+			continue
+		}
+		if header[0] != '*' {
+			return nil, fmt.Errorf("第%d条命令格式错误: 期望*开头，实际为%q", index, header)
+		}
+		count, err := strconv.Atoi(header[1:])
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("第%d条命令格式错误: 无效的参数个数%q", index, header)
+		}
+		// 每个参数至少占用"$0\r\n\r\n"6个字节
+		if count > remaining()/6 {
+			return nil, fmt.Errorf("第%d条命令不完整: 参数个数%d超过剩余内容", index, count)
+		}
+
+		args := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			lengthLine, err := readLine()
+			if err != nil {
+				return nil, fmt.Errorf("第%d条命令不完整", index)
+			}
+			if lengthLine == "" || lengthLine[0] != '$' {
+				return nil, fmt.Errorf("第%d条命令格式错误: 期望$开头，实际为%q", index, lengthLine)
+			}
+			length, err := strconv.Atoi(lengthLine[1:])
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("第%d条命令格式错误: 无效的长度%q", index, lengthLine)
+			}
+			if length > remaining()-2 {
+				return nil, fmt.Errorf("第%d条命令不完整: 参数长度%d超过剩余内容", index, length)
+			}
+			buf := make([]byte, length+2)
+			if _, err := io.ReadFull(reader, buf); err != nil {
+				return nil, fmt.Errorf("第%d条命令不完整", index)
+			}
+			if buf[length] != '\r' || buf[length+1] != '\n' {
+				return nil, fmt.Errorf("第%d条命令格式错误: 参数长度与内容不符", index)
+			}
+			args = append(args, string(buf[:length]))
+		}
+		commands = append(commands, importCommand{line: index, args: args})
+	}
+}
+
+// parseInlineCommands 解析redis-cli命令脚本：每行一条命令，空行与#开头的行忽略
+func parseInlineCommands(content string) ([]importCommand, error) {
+	var commands []importCommand
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxImportBytes)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		args, err := splitCommandArgs(text)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行解析失败: %w", lineNo, err)
+		}
+		if len(args) > 0 {
+			commands = append(commands, importCommand{line: lineNo, args: args})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取导入内容失败: %w", err)
+	}
+	return commands, nil
+}
+
+// splitCommandArgs 按redis-cli的规则拆分参数：双引号内支持\n、\t、\xHH等转义，单引号内只支持\'
+func splitCommandArgs(line string) ([]string, error) {
+	var args []string
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+
+		var b strings.Builder
+		inDouble, inSingle, done := false, false, false
+		for !done {
+			if i >= len(line) {
+				if inDouble || inSingle {
+					return nil, fmt.Errorf("引号未闭合")
+				}
+				break
+			}
+			c := line[i]
+			switch {
+			case inDouble:
+				if c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					v, _ := strconv.ParseUint(line[i+2:i+4], 16, 8)
+					b.WriteByte(byte(v))
+					i += 3
+				} else if c == '\\' && i+1 < len(line) {
+					i++
+					switch line[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 'r':
+						b.WriteByte('\r')
+					case 't':
+						b.WriteByte('\t')
+					case 'b':
+						b.WriteByte('\b')
+					case 'a':
+						b.WriteByte('\a')
+					default:
+						b.WriteByte(line[i])
+					}
+				} else if c == '"' {
+					// 闭合的引号后必须是空白或行尾
+					if i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '\t' {
+						return nil, fmt.Errorf("引号后缺少空格")
+					}
+					done = true
+				} else {
+					b.WriteByte(c)
+				}
+			case inSingle:
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					b.WriteByte('\'')
+				} else if c == '\'' {
+					if i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '\t' {
+						return nil, fmt.Errorf("引号后缺少空格")
+					}
+					done = true
+				} else {
+					b.WriteByte(c)
+				}
+			default:
+				switch c {
+				case ' ', '\t':
+					done = true
+				case '"':
+					inDouble = true
+				case '\'':
+					inSingle = true
+				default:
+					b.WriteByte(c)
+				}
+			}
+			i++
+		}
+		args = append(args, b.String())
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
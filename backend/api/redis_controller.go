@@ -3,7 +3,10 @@ package api
 import (
 	"context"
 	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/my_error"
 	"ev-plugin/backend/response"
+	"ev-plugin/backend/snapshot"
 	"ev-plugin/backend/vo"
 	"fmt"
 	"strconv"
@@ -19,13 +22,26 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	minScanCount              = 100  // 带MATCH过滤时单次SCAN的最小COUNT
+	maxScanCount              = 1000 // 单次SCAN的最大COUNT
+	maxScanRoundsPerPage      = 200  // 单页最多执行的SCAN轮数，避免稀疏匹配时扫描整个keyspace
+	memoryAnalysisConcurrency = 20   // 内存分析时MEMORY USAGE的并发数
+	defaultValuePageSize      = 100  // Key详情默认每页元素数量
+	maxValuePageSize          = 1000 // Key详情每页元素数量上限
+)
+
 // Redis控制器
 type RedisController struct {
 	*BaseController
+	jobManager    *job.Manager
+	snapshotStore *snapshot.Store
 }
 
-func NewRedisController(baseController *BaseController) *RedisController {
-	return &RedisController{baseController}
+func NewRedisController(baseController *BaseController, jobManager *job.Manager) *RedisController {
+	controller := &RedisController{BaseController: baseController, jobManager: jobManager, snapshotStore: snapshot.NewStore()}
+	controller.registerJobRunners()
+	return controller
 }
 
 // isConnectionRefusedError 检查错误是否为连接被拒绝的错误
@@ -105,6 +121,118 @@ func (this *RedisController) executeRedisCommandWithRetry(ctx context.Context, a
 	return nil, fmt.Errorf("Redis命令执行失败，已重试%d次: %w", maxRetries, lastErr)
 }
 
+// scanKeys 从指定游标执行一次SCAN，返回下一个游标和本轮扫描到的keys
+func (this *RedisController) scanKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, cursor, pattern string, count int) (string, []string, error) {
+	args := []interface{}{cursor}
+	if pattern != "" && pattern != "*" {
+		args = append(args, "MATCH", pattern)
+	}
+	args = append(args, "COUNT", strconv.Itoa(count))
+
+	scanResult, err := this.executeRedisCommandWithRetry(ctx, api, database, "SCAN", args...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	scanArray := cast.ToSlice(scanResult)
+	if len(scanArray) != 2 {
+		return "", nil, fmt.Errorf("SCAN结果格式错误: %v", scanResult)
+	}
+
+	var keys []string
+	for _, key := range cast.ToSlice(scanArray[1]) {
+		if keyStr := cast.ToString(key); keyStr != "" {
+			keys = append(keys, keyStr)
+		}
+	}
+
+	return cast.ToString(scanArray[0]), keys, nil
+}
+
+// scanKeysPage 从指定游标开始循环SCAN，凑够约limit个key或扫描轮数达到上限后返回
+// filter不为nil时只保留filter返回true的key；由于SCAN的游标语义无法拆分单轮结果，实际返回数量可能略多于limit
+func (this *RedisController) scanKeysPage(ctx context.Context, api *ev_api.EvApiAdapter, database int, cursor, pattern string, limit int, filter func(key string) bool) ([]string, string, error) {
+	if cursor == "" {
+		cursor = "0"
+	}
+
+	// 带MATCH过滤时每轮命中率较低，适当放大COUNT以减少往返次数
+	scanCount := limit
+	if pattern != "" && pattern != "*" && scanCount < minScanCount {
+		scanCount = minScanCount
+	}
+	if scanCount > maxScanCount {
+		scanCount = maxScanCount
+	}
+
+	var keys []string
+	for round := 0; round < maxScanRoundsPerPage; round++ {
+		nextCursor, batch, err := this.scanKeys(ctx, api, database, cursor, pattern, scanCount)
+		if err != nil {
+			return keys, cursor, err
+		}
+
+		for _, key := range batch {
+			if filter == nil || filter(key) {
+				keys = append(keys, key)
+			}
+		}
+		cursor = nextCursor
+
+		// cursor为"0"表示扫描完成
+		if cursor == "0" || len(keys) >= limit {
+			break
+		}
+	}
+
+	return keys, cursor, nil
+}
+
+// configGet 执行CONFIG GET获取单个配置项的值，兼容RESP2数组与RESP3字典两种返回格式
+func (this *RedisController) configGet(ctx context.Context, api *ev_api.EvApiAdapter, database int, name string) (string, error) {
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, "CONFIG", "GET", name)
+	if err != nil {
+		return "", err
+	}
+
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return cast.ToString(resultMap[name]), nil
+	}
+
+	values := cast.ToSlice(result)
+	for i := 0; i+1 < len(values); i += 2 {
+		if cast.ToString(values[i]) == name {
+			return cast.ToString(values[i+1]), nil
+		}
+	}
+	return "", nil
+}
+
+// serverVersion 从INFO server中获取Redis版本号，如7.2.4
+func (this *RedisController) serverVersion(ctx context.Context, api *ev_api.EvApiAdapter, database int) (string, error) {
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, "INFO", "server")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(cast.ToString(result), "\n") {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); ok {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("INFO server中没有redis_version")
+}
+
+// versionAtLeast 判断版本号是否不低于major.minor
+func versionAtLeast(version string, major, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	actualMajor, actualMinor := cast.ToInt(parts[0]), cast.ToInt(parts[1])
+	return actualMajor > major || (actualMajor == major && actualMinor >= minor)
+}
+
 // GetAllKeysAction 获取Redis所有key
 func (this *RedisController) GetAllKeysAction(ctx *gin.Context) {
 	req := new(dto.RedisKeysRequest)
@@ -159,72 +287,19 @@ func (this *RedisController) GetInfoOverviewAction(ctx *gin.Context) {
 	// 调用基座API
 	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
 
-	// 获取Redis INFO信息
-	infoResult, err := api.RedisExecCommand(ctx, req.Database, "INFO")
-	if err != nil {
-		logger.DefaultLogger.Error("获取Redis INFO失败", "error:", err)
-		this.Error(ctx, err)
-		return
-	}
-
-	// 获取Redis INFO keyspace信息
-	keyspaceResult, err := api.RedisExecCommand(ctx, req.Database, "INFO", "keyspace")
+	// 获取Redis INFO信息，INFO all包含默认不返回的commandstats、errorstats等分段
+	infoResult, err := api.RedisExecCommand(ctx, req.Database, "INFO", "all")
 	if err != nil {
-		logger.DefaultLogger.Error("获取Redis INFO keyspace失败", "error:", err)
-		this.Error(ctx, err)
-		return
-	}
-
-	// 解析INFO信息
-	infoMap := make(map[string]string)
-	if infoResult != nil {
-		infoStr := cast.ToString(infoResult)
-		if infoStr != "" {
-			lines := strings.Split(infoStr, "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "#") || line == "" {
-					continue
-				}
-				kv := strings.SplitN(line, ":", 2)
-				if len(kv) == 2 {
-					infoMap[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
-				}
-			}
-		}
-	}
-
-	// 解析keyspace信息
-	var keyspace []map[string]interface{}
-	if keyspaceResult != nil {
-		keyspaceStr := cast.ToString(keyspaceResult)
-		if keyspaceStr != "" {
-			lines := strings.Split(keyspaceStr, "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "db") {
-					parts := strings.SplitN(line, ":", 2)
-					if len(parts) == 2 {
-						db := parts[0]
-						stats := strings.Split(parts[1], ",")
-						m := map[string]interface{}{"db": db}
-						for _, stat := range stats {
-							kv := strings.SplitN(stat, "=", 2)
-							if len(kv) == 2 {
-								m[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
-							}
-						}
-						keyspace = append(keyspace, m)
-					}
-				}
-			}
+		logger.DefaultLogger.Warn("INFO all执行失败，回退为INFO", "error:", err)
+		infoResult, err = api.RedisExecCommand(ctx, req.Database, "INFO")
+		if err != nil {
+			logger.DefaultLogger.Error("获取Redis INFO失败", "error:", err)
+			this.Error(ctx, err)
+			return
 		}
 	}
 
-	this.Success(ctx, response.SearchSuccess, vo.RedisInfoResponse{
-		Info:     infoMap,
-		Keyspace: keyspace,
-	})
+	this.Success(ctx, response.SearchSuccess, buildInfoResponse(parseInfoSections(cast.ToString(infoResult))))
 }
 
 // GetDatabasesAction 获取Redis数据库列表
@@ -338,10 +413,12 @@ func (this *RedisController) GetMemoryAnalysisAction(ctx *gin.Context) {
 		req.Cursor = "0"
 	}
 
-	logger.DefaultLogger.Debug("开始Redis内存分析（返回所有匹配的Keys） - 将执行MEMORY USAGE命令",
+	logger.DefaultLogger.Debug("开始Redis内存分析（按游标分页） - 将执行MEMORY USAGE命令",
 		"conn_id:", req.EsConnect,
 		"database:", req.Database,
-		"pattern:", req.Pattern)
+		"pattern:", req.Pattern,
+		"count:", req.Count,
+		"cursor:", req.Cursor)
 
 	// 调用基座API
 	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
@@ -352,100 +429,79 @@ func (this *RedisController) GetMemoryAnalysisAction(ctx *gin.Context) {
 		totalCount = cast.ToInt(dbSizeResult)
 	}
 
-	// 扫描所有匹配的keys
-	var allKeys []string
-	cursor := "0"
-
-	logger.DefaultLogger.Debug("开始扫描所有匹配的Keys")
-
-	// 循环扫描所有匹配的keys
-	for {
-		scanResult, err := this.executeRedisCommandWithRetry(ctx, api, req.Database, "SCAN", cursor, "MATCH", req.Pattern, "COUNT", "1000")
-		if err != nil {
-			logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
-			this.Error(ctx, err)
-			return
-		}
-
-		scanArray := cast.ToSlice(scanResult)
-		if len(scanArray) != 2 {
-			logger.DefaultLogger.Error("SCAN结果格式错误", "result:", scanResult)
-			break
-		}
+	// 从请求游标开始扫描，凑够约count个key后停止，不再一次性扫描整个keyspace
+	pageKeys, nextCursor, err := this.scanKeysPage(ctx, api, req.Database, req.Cursor, req.Pattern, req.Count, nil)
+	if err != nil {
+		logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
 
-		// 获取下一个cursor
-		cursor = cast.ToString(scanArray[0])
+	logger.DefaultLogger.Debug("本页扫描完成",
+		"本页Keys:", len(pageKeys),
+		"下一页游标:", nextCursor)
 
-		// 获取keys数组
-		if keysData := scanArray[1]; keysData != nil {
-			keysList := cast.ToSlice(keysData)
-			for _, key := range keysList {
-				keyStr := cast.ToString(key)
-				if keyStr != "" {
-					allKeys = append(allKeys, keyStr)
-				}
-			}
-		}
+	// 分析每个key的内存使用情况 - 使用并发处理
+	keyMemoryInfos, totalSize, _ := this.analyzeKeysMemory(ctx, api, req.Database, pageKeys)
 
-		// cursor为"0"表示扫描完成
-		if cursor == "0" {
-			break
-		}
-	}
+	logger.DefaultLogger.Debug("内存分析完成 - 已执行MEMORY USAGE命令",
+		"处理Keys:", len(keyMemoryInfos),
+		"总大小:", totalSize)
 
-	logger.DefaultLogger.Debug("扫描完成",
-		"总匹配Keys:", len(allKeys))
+	this.Success(ctx, response.SearchSuccess, vo.RedisMemoryAnalysisResponse{
+		Keys:       keyMemoryInfos,
+		TotalKeys:  len(keyMemoryInfos),
+		TotalSize:  totalSize,
+		NextCursor: nextCursor,
+		TotalCount: totalCount,
+	})
+}
 
-	// 分析每个key的内存使用情况 - 使用并发处理
-	var keyMemoryInfos []vo.RedisKeyMemoryInfo
+// analyzeKeysMemory 并发分析一批key的内存使用情况，返回成功分析的结果、总大小以及失败数量
+func (this *RedisController) analyzeKeysMemory(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string) ([]vo.RedisKeyMemoryInfo, int64, int) {
+	keyMemoryInfos := make([]vo.RedisKeyMemoryInfo, 0, len(keys))
 	var totalSize int64 = 0
+	failed := 0
 	var mu sync.Mutex // 保护共享数据
 
-	if len(allKeys) > 0 {
-		// 创建带有并发限制的errgroup
-		g, gctx := errgroup.WithContext(ctx)
-		g.SetLimit(2000) // 控制并发数为10，避免对Redis造成过大压力
+	if len(keys) == 0 {
+		return keyMemoryInfos, totalSize, failed
+	}
 
-		// 为每个key创建一个goroutine
-		for _, key := range allKeys {
-			key := key // 避免闭包问题
-			g.Go(func() error {
-				// 分析单个key的内存信息
-				keyInfo, err := this.analyzeKeyMemory(gctx, api, req.Database, key)
-				if err != nil {
-					logger.DefaultLogger.Error("分析Key内存失败", "key:", key, "error:", err)
-					// 不返回错误，继续处理其他key
-					return nil
-				}
+	// 创建带有并发限制的errgroup
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency) // 控制并发数，避免对Redis造成过大压力
 
-				// 线程安全地添加结果
-				mu.Lock()
-				keyMemoryInfos = append(keyMemoryInfos, keyInfo)
-				totalSize += keyInfo.SizeBytes
-				mu.Unlock()
+	// 为每个key创建一个goroutine
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			// 分析单个key的内存信息
+			keyInfo, err := this.analyzeKeyMemory(gctx, api, database, key)
 
+			// 线程安全地添加结果
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Error("分析Key内存失败", "key:", key, "error:", err)
+				// 不返回错误，继续处理其他key
+				failed++
 				return nil
-			})
-		}
+			}
+			keyMemoryInfos = append(keyMemoryInfos, keyInfo)
+			totalSize += keyInfo.SizeBytes
 
-		// 等待所有goroutine完成
-		if err := g.Wait(); err != nil {
-			logger.DefaultLogger.Error("并发分析内存失败", "error:", err)
-			// 不直接返回错误，允许返回部分结果
-		}
+			return nil
+		})
 	}
 
-	logger.DefaultLogger.Debug("内存分析完成 - 已执行MEMORY USAGE命令",
-		"处理Keys:", len(keyMemoryInfos),
-		"总大小:", totalSize)
+	// 等待所有goroutine完成
+	if err := g.Wait(); err != nil {
+		logger.DefaultLogger.Error("并发分析内存失败", "error:", err)
+		// 不直接返回错误，允许返回部分结果
+	}
 
-	this.Success(ctx, response.SearchSuccess, vo.RedisMemoryAnalysisResponse{
-		Keys:       keyMemoryInfos,
-		TotalKeys:  len(keyMemoryInfos),
-		TotalSize:  totalSize,
-		NextCursor: "0", // 前端分页不需要cursor
-		TotalCount: totalCount,
-	})
+	return keyMemoryInfos, totalSize, failed
 }
 
 // analyzeKeyMemory 分析单个key的内存使用情况
@@ -765,33 +821,154 @@ func (this *RedisController) GetKeyDetailAction(ctx *gin.Context) {
 		}
 	}
 
-	// 根据类型获取值
+	// 获取元素总数，string为字节长度
+	var total int64
+	if keyType == "string" {
+		var strlenResult interface{}
+		strlenResult, err = this.executeRedisCommandWithRetry(ctx, api, req.Database, "STRLEN", req.Key)
+		total = cast.ToInt64(strlenResult)
+	} else {
+		total, err = this.keyElementCount(ctx, api, req.Database, req.Key, keyType)
+	}
+	if err != nil {
+		logger.DefaultLogger.Warn("获取元素数量失败", "key:", req.Key, "type:", keyType, "error:", err)
+	}
+
+	// 只在读取第一页时计算版本号，保证版本号对应编辑开始时的状态
+	// 版本号必须在读取值之前计算：之后才计算的话，两次调用之间的写入会让旧数据配上新版本号，保存时覆盖他人的修改
+	version := ""
+	if req.Cursor == "" && req.Offset == 0 {
+		versionResult, err := this.executeRedisCommandWithRetry(ctx, api, req.Database, "EVAL", keyVersionScript, "1", req.Key)
+		if err != nil {
+			logger.DefaultLogger.Warn("获取Key版本号失败", "key:", req.Key, "error:", err)
+		} else {
+			version = cast.ToString(versionResult)
+		}
+	}
+
+	// 根据类型分页获取值，避免大Key一次性读取全部元素阻塞Redis
 	var value interface{}
+	nextCursor := ""
 	switch keyType {
 	case "string":
 		value, _ = api.RedisExecCommand(ctx, req.Database, "GET", req.Key)
-	case "hash":
-		value, _ = api.RedisExecCommand(ctx, req.Database, "HGETALL", req.Key)
-	case "list":
-		value, _ = api.RedisExecCommand(ctx, req.Database, "LRANGE", req.Key, "0", "-1")
-	case "set":
-		value, _ = api.RedisExecCommand(ctx, req.Database, "SMEMBERS", req.Key)
-	case "zset":
-		value, _ = api.RedisExecCommand(ctx, req.Database, "ZRANGE", req.Key, "0", "-1", "WITHSCORES")
+	case "hash", "set":
+		value, nextCursor, err = this.scanKeyValues(ctx, api, req, keyType)
+	case "list", "zset":
+		value, nextCursor, err = this.rangeKeyValues(ctx, api, req, keyType, total)
+	case "stream":
+		var detail vo.RedisStreamDetail
+		detail, err = this.getStreamDetail(ctx, api, req)
+		value, nextCursor = detail, detail.NextCursor
 	default:
 		value = "unsupported type"
 	}
+	if err != nil {
+		logger.DefaultLogger.Error("获取Key的值失败", "key:", req.Key, "type:", keyType, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
 
 	this.Success(ctx, response.SearchSuccess, vo.RedisKeyDetailResponse{
-		Key:       req.Key,
-		Type:      keyType,
-		SizeBytes: sizeBytes,
-		TTL:       ttl,
-		Value:     value,
+		Key:        req.Key,
+		Type:       keyType,
+		SizeBytes:  sizeBytes,
+		TTL:        ttl,
+		Value:      value,
+		Total:      total,
+		NextCursor: nextCursor,
+		Version:    version,
 	})
 }
 
-// SetKeyAction 保存/更新Redis Key
+// scanKeyValues 使用HSCAN/SSCAN从游标开始读取约一页元素，hash返回[field1, value1, ...]，set返回成员列表
+// 与SCAN一样单轮结果无法拆分，实际返回数量可能略多于页大小，跨页时也可能出现少量重复元素
+func (this *RedisController) scanKeyValues(ctx context.Context, api *ev_api.EvApiAdapter, req *dto.RedisKeyDetailRequest, keyType string) ([]interface{}, string, error) {
+	command := "SSCAN"
+	step := 1
+	if keyType == "hash" {
+		command = "HSCAN"
+		step = 2
+	}
+
+	pageSize := normalizeValuePageSize(req.PageSize)
+	cursor := req.Cursor
+	if cursor == "" {
+		cursor = "0"
+	}
+
+	values := []interface{}{}
+	for round := 0; round < maxScanRoundsPerPage; round++ {
+		result, err := this.executeRedisCommandWithRetry(ctx, api, req.Database, command, req.Key, cursor, "COUNT", strconv.Itoa(pageSize))
+		if err != nil {
+			return nil, "", err
+		}
+
+		scanArray := cast.ToSlice(result)
+		if len(scanArray) != 2 {
+			return nil, "", fmt.Errorf("%s结果格式错误: %v", command, result)
+		}
+		cursor = cast.ToString(scanArray[0])
+		values = append(values, cast.ToSlice(scanArray[1])...)
+
+		if cursor == "0" || len(values)/step >= pageSize {
+			break
+		}
+	}
+
+	if cursor == "0" {
+		cursor = ""
+	}
+	return values, cursor, nil
+}
+
+// rangeKeyValues 使用LRANGE/ZRANGE按偏移量读取一页元素，zset返回[member1, score1, ...]
+func (this *RedisController) rangeKeyValues(ctx context.Context, api *ev_api.EvApiAdapter, req *dto.RedisKeyDetailRequest, keyType string, total int64) (interface{}, string, error) {
+	pageSize := normalizeValuePageSize(req.PageSize)
+	offset := req.Offset
+	if req.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.Cursor); err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("无效的分页游标: %s", req.Cursor)
+		}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	start := strconv.Itoa(offset)
+	stop := strconv.Itoa(offset + pageSize - 1)
+
+	var result interface{}
+	var err error
+	if keyType == "list" {
+		result, err = this.executeRedisCommandWithRetry(ctx, api, req.Database, "LRANGE", req.Key, start, stop)
+	} else {
+		result, err = this.executeRedisCommandWithRetry(ctx, api, req.Database, "ZRANGE", req.Key, start, stop, "WITHSCORES")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(offset+pageSize) < total {
+		nextCursor = strconv.Itoa(offset + pageSize)
+	}
+	return result, nextCursor, nil
+}
+
+// normalizeValuePageSize 规范化Key详情的分页大小
+func normalizeValuePageSize(pageSize int) int {
+	if pageSize <= 0 {
+		return defaultValuePageSize
+	}
+	if pageSize > maxValuePageSize {
+		return maxValuePageSize
+	}
+	return pageSize
+}
+
+// SetKeyAction 保存/更新Redis Key - 整体替换在一个Lua脚本中完成，包含TTL与版本号检查
 func (this *RedisController) SetKeyAction(ctx *gin.Context) {
 	req := new(dto.RedisSetKeyRequest)
 	err := ctx.BindJSON(req)
@@ -805,153 +982,157 @@ func (this *RedisController) SetKeyAction(ctx *gin.Context) {
 	// 调用基座API
 	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
 
-	// 根据类型保存数据
-	switch req.Type {
-	case "string":
-		err = this.setStringKey(ctx, api, req)
-	case "hash":
-		err = this.setHashKey(ctx, api, req)
-	case "list":
-		err = this.setListKey(ctx, api, req)
-	case "set":
-		err = this.setSetKey(ctx, api, req)
-	case "zset":
-		err = this.setZSetKey(ctx, api, req)
-	default:
-		this.Error(ctx, fmt.Errorf("不支持的数据类型: %s", req.Type))
-		return
-	}
-
+	err = this.replaceKey(ctx, api, req)
 	if err != nil {
-		logger.DefaultLogger.Error("保存Redis Key失败", "key:", req.Key, "type:", req.Type, "error:", err)
+		logger.DefaultLogger.Error("保存Redis Key失败", "key:", req.Key, "type:", req.Type, "version:", req.Version, "error:", err)
 		this.Error(ctx, err)
 		return
 	}
 
-	// 设置TTL
-	if req.TTL > 0 {
-		_, err = api.RedisExecCommand(ctx, req.Database, "EXPIRE", req.Key, strconv.FormatInt(req.TTL, 10))
-		if err != nil {
-			logger.DefaultLogger.Warn("设置TTL失败", "key:", req.Key, "ttl:", req.TTL, "error:", err)
-		}
-	}
-
 	this.Success(ctx, response.SearchSuccess, vo.RedisOperationResponse{
 		Success: true,
 		Message: "保存成功",
 	})
 }
 
-// setStringKey 保存String类型的Key
-func (this *RedisController) setStringKey(ctx *gin.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
-	value := cast.ToString(req.Value)
-	_, err := api.RedisExecCommand(ctx, req.Database, "SET", req.Key, value)
-	return err
-}
-
-// setHashKey 保存Hash类型的Key
-func (this *RedisController) setHashKey(ctx *gin.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
-	// 先删除原有的hash
-	_, _ = api.RedisExecCommand(ctx, req.Database, "DEL", req.Key)
-
-	// 解析hash数据
-	if hashData, ok := req.Value.(map[string]interface{}); ok {
-		if len(hashData) == 0 {
-			return nil // 空hash不需要创建
-		}
+// replaceKey 在一个Lua脚本中整体替换Key，包含TTL与版本号检查，版本号不一致时返回KeyVersionConflict错误
+func (this *RedisController) replaceKey(ctx context.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
+	payload, err := replaceKeyPayload(req)
+	if err != nil {
+		return err
+	}
 
-		// 构建HMSET命令参数
-		args := []interface{}{"HMSET", req.Key}
-		for field, value := range hashData {
-			args = append(args, field, cast.ToString(value))
-		}
+	args := []interface{}{"EVAL", replaceKeyScript, "2", req.Key, replaceTempKey(req.Key), req.Type, strconv.FormatInt(req.TTL, 10), req.Version}
+	args = append(args, payload...)
 
-		_, err := api.RedisExecCommand(ctx, req.Database, args...)
-		return err
+	_, err = api.RedisExecCommand(ctx, req.Database, args...)
+	if isKeyVersionConflict(err) {
+		return my_error.NewError("Key在编辑期间已被修改，请刷新后重新编辑", my_error.KeyVersionConflict)
+	}
+	if isStreamHasGroups(err) {
+		return fmt.Errorf("原Stream存在消费组，整体保存会丢失消费组与待确认消息，请逐条修改或先删除消费组")
+	}
+	if err != nil {
+		return fmt.Errorf("保存失败，原有数据未被修改: %w", err)
 	}
+	return nil
+}
 
-	return fmt.Errorf("invalid hash data format")
+// replaceKeyPayload 根据类型构建写入参数
+func replaceKeyPayload(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	switch req.Type {
+	case "string":
+		return stringReplaceArgs(req), nil
+	case "hash":
+		return hashReplaceArgs(req)
+	case "list":
+		return listReplaceArgs(req)
+	case "set":
+		return setReplaceArgs(req)
+	case "zset":
+		return zsetReplaceArgs(req)
+	case "stream":
+		return streamReplaceArgs(req)
+	default:
+		return nil, fmt.Errorf("不支持的数据类型: %s", req.Type)
+	}
 }
 
-// setListKey 保存List类型的Key
-func (this *RedisController) setListKey(ctx *gin.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
-	// 先删除原有的list
-	_, _ = api.RedisExecCommand(ctx, req.Database, "DEL", req.Key)
+// stringReplaceArgs 构建String类型的写入参数
+func stringReplaceArgs(req *dto.RedisSetKeyRequest) []interface{} {
+	return []interface{}{cast.ToString(req.Value)}
+}
 
-	// 解析list数据
-	if listData, ok := req.Value.([]interface{}); ok {
-		if len(listData) == 0 {
-			return nil // 空list不需要创建
+// hashReplaceArgs 构建Hash类型的写入参数：field1, value1, ...
+// value可以是{field: value}，也可以是Key详情与导出使用的[field1, value1, ...]
+func hashReplaceArgs(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	switch hashData := req.Value.(type) {
+	case map[string]interface{}:
+		args := make([]interface{}, 0, len(hashData)*2)
+		for field, value := range hashData {
+			args = append(args, field, cast.ToString(value))
 		}
-
-		// 使用RPUSH添加所有元素
-		args := []interface{}{"RPUSH", req.Key}
-		for _, item := range listData {
+		return args, nil
+	case []interface{}:
+		if len(hashData)%2 != 0 {
+			return nil, fmt.Errorf("invalid hash data format")
+		}
+		args := make([]interface{}, 0, len(hashData))
+		for _, item := range hashData {
 			args = append(args, cast.ToString(item))
 		}
-
-		_, err := api.RedisExecCommand(ctx, req.Database, args...)
-		return err
+		return args, nil
+	default:
+		return nil, fmt.Errorf("invalid hash data format")
 	}
-
-	return fmt.Errorf("invalid list data format")
 }
 
-// setSetKey 保存Set类型的Key
-func (this *RedisController) setSetKey(ctx *gin.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
-	// 先删除原有的set
-	_, _ = api.RedisExecCommand(ctx, req.Database, "DEL", req.Key)
-
-	// 解析set数据
-	if setData, ok := req.Value.([]interface{}); ok {
-		if len(setData) == 0 {
-			return nil // 空set不需要创建
-		}
+// listReplaceArgs 构建List类型的写入参数
+func listReplaceArgs(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	listData, ok := req.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid list data format")
+	}
 
-		// 使用SADD添加所有成员
-		args := []interface{}{"SADD", req.Key}
-		for _, member := range setData {
-			args = append(args, cast.ToString(member))
-		}
+	args := make([]interface{}, 0, len(listData))
+	for _, item := range listData {
+		args = append(args, cast.ToString(item))
+	}
+	return args, nil
+}
 
-		_, err := api.RedisExecCommand(ctx, req.Database, args...)
-		return err
+// setReplaceArgs 构建Set类型的写入参数
+func setReplaceArgs(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	setData, ok := req.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set data format")
 	}
 
-	return fmt.Errorf("invalid set data format")
+	args := make([]interface{}, 0, len(setData))
+	for _, member := range setData {
+		args = append(args, cast.ToString(member))
+	}
+	return args, nil
 }
 
-// setZSetKey 保存ZSet类型的Key
-func (this *RedisController) setZSetKey(ctx *gin.Context, api *ev_api.EvApiAdapter, req *dto.RedisSetKeyRequest) error {
-	// 先删除原有的zset
-	_, _ = api.RedisExecCommand(ctx, req.Database, "DEL", req.Key)
-
-	// 解析zset数据
-	if zsetData, ok := req.Value.([]interface{}); ok {
-		if len(zsetData) == 0 {
-			return nil // 空zset不需要创建
-		}
+// zsetReplaceArgs 构建ZSet类型的写入参数：score1, member1, ...
+// value可以是[{member, score}]，也可以是Key详情与导出使用的[member1, score1, ...]
+func zsetReplaceArgs(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	zsetData, ok := req.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid zset data format")
+	}
 
-		// 使用ZADD添加所有成员
-		args := []interface{}{"ZADD", req.Key}
-		for _, item := range zsetData {
-			if itemMap, ok := item.(map[string]interface{}); ok {
-				score := cast.ToFloat64(itemMap["score"])
-				member := cast.ToString(itemMap["member"])
-				args = append(args, score, member)
+	args := make([]interface{}, 0, len(zsetData)*2)
+	if len(zsetData) > 0 {
+		if _, ok := zsetData[0].(map[string]interface{}); !ok {
+			if len(zsetData)%2 != 0 {
+				return nil, fmt.Errorf("invalid zset data format")
+			}
+			for i := 0; i < len(zsetData); i += 2 {
+				score, err := cast.ToFloat64E(zsetData[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid zset score: %v", zsetData[i+1])
+				}
+				args = append(args, strconv.FormatFloat(score, 'f', -1, 64), cast.ToString(zsetData[i]))
 			}
+			return args, nil
 		}
+	}
 
-		if len(args) > 2 {
-			_, err := api.RedisExecCommand(ctx, req.Database, args...)
-			return err
+	for _, item := range zsetData {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid zset data format")
 		}
+		score := cast.ToFloat64(itemMap["score"])
+		member := cast.ToString(itemMap["member"])
+		args = append(args, strconv.FormatFloat(score, 'f', -1, 64), member)
 	}
-
-	return fmt.Errorf("invalid zset data format")
+	return args, nil
 }
 
-// SearchKeysAction 搜索Redis Keys - 使用SCAN + strings.Contains方式
+// SearchKeysAction 搜索Redis Keys - 按游标分页的SCAN，contains/glob/prefix模式下推到SCAN MATCH，regex模式在插件侧过滤
 func (this *RedisController) SearchKeysAction(ctx *gin.Context) {
 	req := new(dto.RedisSearchKeysRequest)
 	err := ctx.BindJSON(req)
@@ -967,18 +1148,25 @@ func (this *RedisController) SearchKeysAction(ctx *gin.Context) {
 	if req.Cursor == "" {
 		req.Cursor = "0"
 	}
-	if req.SearchText == "" {
-		req.SearchText = "" // 空字符串表示搜索所有
+	if req.MatchMode == "" {
+		req.MatchMode = KeyMatchContains
 	}
 
 	logger.DefaultLogger.Debug("开始Redis Key搜索（后端搜索） - 不会执行MEMORY USAGE命令",
 		"conn_id:", req.EsConnect,
 		"database:", req.Database,
 		"search_text:", req.SearchText,
+		"match_mode:", req.MatchMode,
 		"count:", req.Count,
 		"cursor:", req.Cursor,
 		"case_sensitive:", req.CaseSensitive)
 
+	pattern, filter, err := buildKeyMatcher(req.MatchMode, req.SearchText, req.CaseSensitive)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
 	// 调用基座API
 	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
 
@@ -990,102 +1178,38 @@ func (this *RedisController) SearchKeysAction(ctx *gin.Context) {
 		}
 	}
 
-	// 先扫描全部keys，然后使用strings.Contains进行过滤
-	var allKeys []string
-	var matchedKeys []string
-	cursor := "0"
-
-	// 准备搜索文本
-	searchText := req.SearchText
-	if !req.CaseSensitive {
-		searchText = strings.ToLower(searchText)
-	}
-
-	logger.DefaultLogger.Debug("开始扫描全部Keys进行搜索")
-
-	// 循环扫描所有keys
-	for {
-		scanResult, err := api.RedisExecCommand(ctx, req.Database, "SCAN", cursor, "COUNT", "1000")
-		if err != nil {
-			logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
-			this.Error(ctx, err)
-			return
-		}
-
-		scanArray := cast.ToSlice(scanResult)
-		if len(scanArray) != 2 {
-			logger.DefaultLogger.Error("SCAN结果格式错误", "result:", scanResult)
-			break
-		}
-
-		// 获取下一个cursor
-		cursor = cast.ToString(scanArray[0])
-
-		// 获取keys数组
-		if keysData := scanArray[1]; keysData != nil {
-			keysList := cast.ToSlice(keysData)
-			for _, key := range keysList {
-				keyStr := cast.ToString(key)
-				if keyStr != "" {
-					allKeys = append(allKeys, keyStr)
-				}
-			}
-		}
-
-		// cursor为"0"表示扫描完成
-		if cursor == "0" {
-			break
-		}
-	}
-
-	logger.DefaultLogger.Debug("扫描完成，开始过滤",
-		"总Keys数量:", len(allKeys),
-		"搜索文本:", req.SearchText)
-
-	// 使用strings.Contains进行过滤
-	for _, key := range allKeys {
-		keyToCheck := key
-		if !req.CaseSensitive {
-			keyToCheck = strings.ToLower(key)
-		}
-
-		// 如果搜索文本为空，返回所有keys；否则使用contains匹配
-		if searchText == "" || strings.Contains(keyToCheck, searchText) {
-			matchedKeys = append(matchedKeys, key)
-		}
+	matchedKeys, nextCursor, err := this.scanKeysPage(ctx, api, req.Database, req.Cursor, pattern, req.Count, filter)
+	if err != nil {
+		logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
 	}
 
-	// 保存筛选后的总数量
-	totalMatchedCount := len(matchedKeys)
-
-	logger.DefaultLogger.Debug("过滤完成，准备返回所有匹配的Keys",
-		"匹配的Keys数量:", totalMatchedCount)
-
 	// Key搜索不需要分析内存等详细信息，只返回key名称列表
-	var keyMemoryInfos []vo.RedisKeyMemoryInfo
-
-	// 将匹配的key名称转换为简单的结构体列表
+	keyMemoryInfos := make([]vo.RedisKeyMemoryInfo, 0, len(matchedKeys))
 	for _, key := range matchedKeys {
-		keyInfo := vo.RedisKeyMemoryInfo{
+		keyMemoryInfos = append(keyMemoryInfos, vo.RedisKeyMemoryInfo{
 			Key:       key,
 			SizeBytes: 0,  // 搜索时不获取大小信息
 			Type:      "", // 搜索时不获取类型信息
 			TTL:       -1, // 搜索时不获取TTL信息
-		}
-		keyMemoryInfos = append(keyMemoryInfos, keyInfo)
+		})
 	}
 
 	logger.DefaultLogger.Debug("Redis Key搜索完成 - 未执行任何MEMORY USAGE命令",
-		"匹配Keys:", len(keyMemoryInfos))
+		"匹配Keys:", len(keyMemoryInfos),
+		"下一页游标:", nextCursor)
 
 	this.Success(ctx, response.SearchSuccess, vo.RedisSearchKeysResponse{
 		Keys:         keyMemoryInfos,
 		TotalKeys:    len(keyMemoryInfos), // 返回的key数量
 		TotalSize:    0,                   // 搜索时不统计总大小
-		NextCursor:   "0",                 // 前端分页不需要cursor
-		TotalCount:   totalCount,          // 数据库中的总key数量
+		NextCursor:   nextCursor,
+		TotalCount:   totalCount, // 数据库中的总key数量
 		SearchText:   req.SearchText,
-		MatchedCount: totalMatchedCount, // 搜索匹配的总数量
+		MatchMode:    req.MatchMode,
+		MatchedCount: len(matchedKeys), // 本页匹配的数量
+		Partial:      nextCursor != "0",
 	})
 }
 
@@ -0,0 +1,378 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+// 值摘要方式
+const (
+	DiffDigestDump    = "dump"    // DUMP序列化值的SHA1，速度快，但不同版本或编码的Redis之间可能不一致
+	DiffDigestContent = "content" // 按类型读取内容计算的SHA1，与编码无关，适合跨版本比较，大Key较慢
+	DiffDigestNone    = "none"    // 只比较类型与TTL
+)
+
+const (
+	defaultDiffMaxKeys    = 1000            // 每类差异默认保留的Key数量
+	maxDiffDigestKeyBytes = 4 * 1024 * 1024 // 计算值摘要的单个Key内存上限，超过时不计算摘要，记为未校验
+)
+
+// TTL类别
+const (
+	ttlClassNoExpiry = "no_expiry"
+	ttlClassExpiring = "expiring"
+)
+
+// keyDigestScript 获取Key的类型、剩余TTL与值摘要，Key不存在时返回nil
+// content模式下每个元素按"长度:内容"编码后拼接，hash与set先排序，避免元素顺序与内部编码影响结果
+// 摘要的耗时与Key大小成正比，ARGV[2]大于0时先用MEMORY USAGE检查，超过上限则不计算摘要并标记为未校验
+//
+// KEYS[1] Key，ARGV[1] 摘要方式 (dump, content, none)，ARGV[2] 计算摘要的内存上限
+// 返回{类型, 剩余毫秒数, 摘要, 是否未校验(1/0)}
+const keyDigestScript = `
+local key = KEYS[1]
+local keyType = redis.call('TYPE', key)['ok']
+if keyType == 'none' then
+	return nil
+end
+local pttl = redis.call('PTTL', key)
+
+local maxBytes = tonumber(ARGV[2])
+if ARGV[1] ~= 'none' and maxBytes > 0 then
+	local memory = redis.pcall('MEMORY', 'USAGE', key)
+	if type(memory) == 'number' and memory > maxBytes then
+		return {keyType, pttl, '', 1}
+	end
+end
+
+local function encode(parts)
+	local buf = {}
+	for i, p in ipairs(parts) do
+		buf[i] = #p .. ':' .. p
+	end
+	return redis.sha1hex(table.concat(buf))
+end
+
+local digest = ''
+if ARGV[1] == 'dump' then
+	digest = redis.sha1hex(redis.call('DUMP', key))
+elseif ARGV[1] == 'content' then
+	local parts
+	if keyType == 'string' then
+		parts = {redis.call('GET', key)}
+	elseif keyType == 'hash' then
+		local flat = redis.call('HGETALL', key)
+		parts = {}
+		for i = 1, #flat, 2 do
+			parts[#parts + 1] = #flat[i] .. ':' .. flat[i] .. flat[i + 1]
+		end
+		table.sort(parts)
+	elseif keyType == 'list' then
+		parts = redis.call('LRANGE', key, 0, -1)
+	elseif keyType == 'set' then
+		parts = redis.call('SMEMBERS', key)
+		table.sort(parts)
+	elseif keyType == 'zset' then
+		parts = redis.call('ZRANGE', key, 0, -1, 'WITHSCORES')
+	elseif keyType == 'stream' then
+		parts = {}
+		for _, entry in ipairs(redis.call('XRANGE', key, '-', '+')) do
+			parts[#parts + 1] = entry[1]
+			for _, v in ipairs(entry[2]) do
+				parts[#parts + 1] = v
+			end
+		end
+	end
+	if parts then
+		digest = encode(parts)
+	else
+		-- 模块类型无法按内容读取，退化为DUMP
+		digest = redis.sha1hex(redis.call('DUMP', key))
+	end
+end
+
+return {keyType, pttl, digest, 0}
+`
+
+// KeyspaceDiffAction 比较两个数据源（或同一数据源的两个库）在匹配条件下的keyspace差异，以后台任务执行
+func (this *RedisController) KeyspaceDiffAction(ctx *gin.Context) {
+	req := new(dto.RedisKeyspaceDiffRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.TargetConnect <= 0 {
+		this.Error(ctx, fmt.Errorf("请选择对比的数据源"))
+		return
+	}
+	if req.TargetConnect == req.EsConnect && req.TargetDatabase == req.Database {
+		this.Error(ctx, fmt.Errorf("不能与自身对比"))
+		return
+	}
+	if err := normalizeDiffParams(&req.RedisKeyspaceDiffParams); err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	params, err := json.Marshal(req.RedisKeyspaceDiffParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("启动keyspace对比任务", "conn_id:", req.EsConnect, "database:", req.Database,
+		"target_connect:", req.TargetConnect, "target_database:", req.TargetDatabase,
+		"pattern:", req.Pattern, "digest_mode:", req.DigestMode)
+
+	j, err := this.jobManager.Start(ctx, JobKindKeyspaceDiff, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动keyspace对比任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// keyspaceDiffJob keyspace对比任务 - 先扫描源库逐个比较两边的Key，再扫描目标库找出只在目标存在的Key
+// 两轮都按批处理，不在内存中保存完整的Key集合
+func (this *RedisController) keyspaceDiffJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisKeyspaceDiffParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if err := normalizeDiffParams(params); err != nil {
+		return nil, err
+	}
+	pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	source := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+	target := ev_api.NewEvWrapApi(params.TargetConnect, j.UserId)
+
+	var total int64
+	if result, err := this.executeRedisCommandWithRetry(ctx, source, j.Database, "DBSIZE"); err == nil && result != nil {
+		total += cast.ToInt64(result)
+	}
+	if result, err := this.executeRedisCommandWithRetry(ctx, target, params.TargetDatabase, "DBSIZE"); err == nil && result != nil {
+		total += cast.ToInt64(result)
+	}
+	j.SetTotal(total)
+
+	result := &vo.RedisKeyspaceDiffResult{
+		Pattern:        pattern,
+		DigestMode:     params.DigestMode,
+		TargetConnect:  params.TargetConnect,
+		TargetDatabase: params.TargetDatabase,
+		OnlyInSource:   []string{},
+		OnlyInTarget:   []string{},
+		Different:      []vo.RedisKeyDiff{},
+		Unverified:     []string{},
+	}
+	var mu sync.Mutex // 保护result
+
+	// 第一轮：源库中的Key与目标库逐个比较
+	result.ScannedSource, err = this.scanDiffKeys(ctx, j, source, j.Database, pattern, filter, func(gctx context.Context, key string) error {
+		sourceDigest, err := this.keyDigest(gctx, source, j.Database, key, params.DigestMode)
+		if err != nil || sourceDigest == nil {
+			return err // 源Key在扫描后已被删除时忽略
+		}
+		targetDigest, err := this.keyDigest(gctx, target, params.TargetDatabase, key, params.DigestMode)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if targetDigest == nil {
+			result.OnlyInSourceCount++
+			if len(result.OnlyInSource) < params.MaxKeys {
+				result.OnlyInSource = append(result.OnlyInSource, key)
+			}
+			return nil
+		}
+		differences := compareKeyDigest(sourceDigest, targetDigest)
+		if len(differences) == 0 {
+			if sourceDigest.Unverified || targetDigest.Unverified {
+				// 类型与TTL一致，但值过大未计算摘要，无法判断是否一致
+				result.UnverifiedCount++
+				if len(result.Unverified) < params.MaxKeys {
+					result.Unverified = append(result.Unverified, key)
+				}
+				return nil
+			}
+			result.IdenticalCount++
+			return nil
+		}
+		result.DifferentCount++
+		if len(result.Different) < params.MaxKeys {
+			result.Different = append(result.Different, vo.RedisKeyDiff{
+				Key:         key,
+				Differences: differences,
+				Source:      *sourceDigest,
+				Target:      *targetDigest,
+			})
+		}
+		return nil
+	}, &result.FailedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// 第二轮：找出只在目标库存在的Key
+	result.ScannedTarget, err = this.scanDiffKeys(ctx, j, target, params.TargetDatabase, pattern, filter, func(gctx context.Context, key string) error {
+		exists, err := this.executeRedisCommandWithRetry(gctx, source, j.Database, "EXISTS", key)
+		if err != nil {
+			return err
+		}
+		if cast.ToInt64(exists) == 1 {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.OnlyInTargetCount++
+		if len(result.OnlyInTarget) < params.MaxKeys {
+			result.OnlyInTarget = append(result.OnlyInTarget, key)
+		}
+		return nil
+	}, &result.FailedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Truncated = result.OnlyInSourceCount > int64(len(result.OnlyInSource)) ||
+		result.OnlyInTargetCount > int64(len(result.OnlyInTarget)) ||
+		result.DifferentCount > int64(len(result.Different)) ||
+		result.UnverifiedCount > int64(len(result.Unverified))
+
+	return result, nil
+}
+
+// scanDiffKeys 扫描一个库中匹配的Key并发交给compare处理，单个Key处理失败只计数不终止任务，返回扫描的Key数量
+func (this *RedisController) scanDiffKeys(ctx context.Context, j *job.Job, api *ev_api.EvApiAdapter, database int, pattern string,
+	filter func(key string) bool, compare func(gctx context.Context, key string) error, failed *int64) (int64, error) {
+	var scanned int64
+	var mu sync.Mutex // 保护failed
+
+	cursor := "0"
+	for {
+		if err := ctx.Err(); err != nil {
+			return scanned, err
+		}
+
+		nextCursor, keys, err := this.scanKeys(ctx, api, database, cursor, pattern, maxScanCount)
+		if err != nil {
+			return scanned, err
+		}
+		scanned += int64(len(keys))
+
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(memoryAnalysisConcurrency)
+		for _, key := range keys {
+			key := key // 避免闭包问题
+			if filter != nil && !filter(key) {
+				continue
+			}
+			g.Go(func() error {
+				if err := compare(gctx, key); err != nil {
+					logger.DefaultLogger.Debug("对比Key失败", "key:", key, "error:", err)
+					mu.Lock()
+					*failed++
+					mu.Unlock()
+					j.AddFailed(1)
+				}
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		j.AddProcessed(int64(len(keys)))
+		j.SetMessage(fmt.Sprintf("已扫描db%d中的%d个Key", database, scanned))
+
+		cursor = nextCursor
+		if cursor == "0" {
+			break
+		}
+	}
+
+	return scanned, nil
+}
+
+// keyDigest 获取Key的类型、TTL类别与值摘要，Key不存在时返回nil
+func (this *RedisController) keyDigest(ctx context.Context, api *ev_api.EvApiAdapter, database int, key, digestMode string) (*vo.RedisKeyDigest, error) {
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, "EVAL", keyDigestScript, "1", key, digestMode,
+		strconv.Itoa(maxDiffDigestKeyBytes))
+	if err != nil || result == nil {
+		return nil, err
+	}
+	reply := cast.ToSlice(result)
+	if len(reply) != 4 {
+		return nil, fmt.Errorf("摘要脚本返回格式错误")
+	}
+
+	digest := &vo.RedisKeyDigest{
+		Type:       cast.ToString(reply[0]),
+		TtlClass:   ttlClassExpiring,
+		Digest:     cast.ToString(reply[2]),
+		Unverified: cast.ToInt64(reply[3]) == 1,
+	}
+	if cast.ToInt64(reply[1]) < 0 {
+		digest.TtlClass = ttlClassNoExpiry
+	}
+	return digest, nil
+}
+
+// compareKeyDigest 返回两边不一致的项 (type, ttl, value)，类型不同或任一边未计算摘要时不再比较值
+func compareKeyDigest(source, target *vo.RedisKeyDigest) []string {
+	var differences []string
+	if source.Type != target.Type {
+		differences = append(differences, "type")
+	}
+	if source.TtlClass != target.TtlClass {
+		differences = append(differences, "ttl")
+	}
+	if source.Type == target.Type && !source.Unverified && !target.Unverified && source.Digest != target.Digest {
+		differences = append(differences, "value")
+	}
+	return differences
+}
+
+// normalizeDiffParams 校验并填充keyspace对比参数的默认值，匹配模式为空时对比全部Key
+func normalizeDiffParams(params *dto.RedisKeyspaceDiffParams) error {
+	if params.Pattern == "" {
+		params.Pattern = "*"
+		params.MatchMode = KeyMatchGlob
+	}
+	if params.MaxKeys <= 0 {
+		params.MaxKeys = defaultDiffMaxKeys
+	}
+	params.DigestMode = strings.ToLower(params.DigestMode)
+	switch params.DigestMode {
+	case "":
+		params.DigestMode = DiffDigestDump
+	case DiffDigestDump, DiffDigestContent, DiffDigestNone:
+	default:
+		return fmt.Errorf("不支持的摘要方式: %s", params.DigestMode)
+	}
+	return nil
+}
@@ -0,0 +1,391 @@
+package api
+
+import (
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+// listRemoveAtScript 删除指定下标上的元素：先确认元素未被修改，再用占位值替换并LREM，整个过程在Redis中原子执行
+const listRemoveAtScript = `
+local current = redis.call('LINDEX', KEYS[1], ARGV[1])
+if current ~= ARGV[2] then
+	return -1
+end
+redis.call('LSET', KEYS[1], ARGV[1], ARGV[3])
+return redis.call('LREM', KEYS[1], 1, ARGV[3])
+`
+
+// HashSetAction 设置Hash的单个字段 (HSET / HSETNX)
+func (this *RedisController) HashSetAction(ctx *gin.Context) {
+	req := new(dto.RedisHashSetRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || req.Field == "" {
+		this.Error(ctx, fmt.Errorf("key和字段名不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("设置Hash字段", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "field:", req.Field)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	command := "HSET"
+	if req.OnlyNew {
+		command = "HSETNX"
+	}
+	result, err := api.RedisExecCommand(ctx, req.Database, command, req.Key, req.Field, req.Value)
+	if err != nil {
+		logger.DefaultLogger.Error("设置Hash字段失败", "key:", req.Key, "field:", req.Field, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// HashDeleteAction 删除Hash的字段 (HDEL)
+func (this *RedisController) HashDeleteAction(ctx *gin.Context) {
+	req := new(dto.RedisHashDeleteRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || len(req.Fields) == 0 {
+		this.Error(ctx, fmt.Errorf("key和字段名不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("删除Hash字段", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "fields:", len(req.Fields))
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"HDEL", req.Key}
+	for _, field := range req.Fields {
+		args = append(args, field)
+	}
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("删除Hash字段失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// ListSetAction 按下标修改List元素 (LSET)
+func (this *RedisController) ListSetAction(ctx *gin.Context) {
+	req := new(dto.RedisListSetRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("修改List元素", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "index:", req.Index)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	_, err = api.RedisExecCommand(ctx, req.Database, "LSET", req.Key, strconv.FormatInt(req.Index, 10), req.Value)
+	if err != nil {
+		logger.DefaultLogger.Error("修改List元素失败", "key:", req.Key, "index:", req.Index, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: 1,
+	})
+}
+
+// ListInsertAction 插入List元素，BEFORE/AFTER使用LINSERT，HEAD/TAIL使用LPUSH/RPUSH
+func (this *RedisController) ListInsertAction(ctx *gin.Context) {
+	req := new(dto.RedisListInsertRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+
+	position := strings.ToUpper(req.Position)
+	if position == "" {
+		position = "TAIL"
+	}
+
+	var args []interface{}
+	switch position {
+	case "BEFORE", "AFTER":
+		args = []interface{}{"LINSERT", req.Key, position, req.Pivot, req.Value}
+	case "HEAD":
+		args = []interface{}{"LPUSH", req.Key, req.Value}
+	case "TAIL":
+		args = []interface{}{"RPUSH", req.Key, req.Value}
+	default:
+		this.Error(ctx, fmt.Errorf("不支持的插入位置: %s", req.Position))
+		return
+	}
+
+	logger.DefaultLogger.Debug("插入List元素", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "position:", position)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("插入List元素失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	length := cast.ToInt64(result)
+	if length == -1 {
+		this.Error(ctx, fmt.Errorf("参照元素不存在: %s", req.Pivot))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: length,
+	})
+}
+
+// ListRemoveAction 删除List元素，指定index时只删除该下标上的元素，否则按LREM语义删除
+func (this *RedisController) ListRemoveAction(ctx *gin.Context) {
+	req := new(dto.RedisListRemoveRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("删除List元素", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "count:", req.Count)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	var result interface{}
+	if req.Index != nil {
+		result, err = api.RedisExecCommand(ctx, req.Database, "EVAL", listRemoveAtScript, "1", req.Key,
+			strconv.FormatInt(*req.Index, 10), req.Value, newTombstone())
+	} else {
+		result, err = api.RedisExecCommand(ctx, req.Database, "LREM", req.Key, strconv.FormatInt(req.Count, 10), req.Value)
+	}
+	if err != nil {
+		logger.DefaultLogger.Error("删除List元素失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	affected := cast.ToInt64(result)
+	if req.Index != nil && affected == -1 {
+		this.Error(ctx, fmt.Errorf("下标%d上的元素已被修改，请刷新后重试", *req.Index))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: affected,
+	})
+}
+
+// SetAddAction 添加Set成员 (SADD)
+func (this *RedisController) SetAddAction(ctx *gin.Context) {
+	this.setMembersCommand(ctx, "SADD")
+}
+
+// SetRemoveAction 删除Set成员 (SREM)
+func (this *RedisController) SetRemoveAction(ctx *gin.Context) {
+	this.setMembersCommand(ctx, "SREM")
+}
+
+func (this *RedisController) setMembersCommand(ctx *gin.Context, command string) {
+	req := new(dto.RedisSetMembersRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || len(req.Members) == 0 {
+		this.Error(ctx, fmt.Errorf("key和成员不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("修改Set成员", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"command:", command, "members:", len(req.Members))
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{command, req.Key}
+	for _, member := range req.Members {
+		args = append(args, member)
+	}
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("修改Set成员失败", "key:", req.Key, "command:", command, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// ZSetAddAction 添加或更新ZSet成员 (ZADD)，返回新增及分数发生变化的成员数量
+func (this *RedisController) ZSetAddAction(ctx *gin.Context) {
+	req := new(dto.RedisZSetAddRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || len(req.Members) == 0 {
+		this.Error(ctx, fmt.Errorf("key和成员不能为空"))
+		return
+	}
+
+	args := []interface{}{"ZADD", req.Key}
+	condition := strings.ToUpper(req.Condition)
+	switch condition {
+	case "":
+	case "NX", "XX", "GT", "LT":
+		args = append(args, condition)
+	default:
+		this.Error(ctx, fmt.Errorf("不支持的写入条件: %s", req.Condition))
+		return
+	}
+	args = append(args, "CH")
+	for _, member := range req.Members {
+		args = append(args, strconv.FormatFloat(member.Score, 'f', -1, 64), member.Member)
+	}
+
+	logger.DefaultLogger.Debug("添加ZSet成员", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"condition:", condition, "members:", len(req.Members))
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("添加ZSet成员失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// ZSetIncrByAction 增加ZSet成员的分数 (ZINCRBY)
+func (this *RedisController) ZSetIncrByAction(ctx *gin.Context) {
+	req := new(dto.RedisZSetIncrByRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || req.Member == "" {
+		this.Error(ctx, fmt.Errorf("key和成员不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("增加ZSet成员分数", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"member:", req.Member, "increment:", req.Increment)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, "ZINCRBY", req.Key,
+		strconv.FormatFloat(req.Increment, 'f', -1, 64), req.Member)
+	if err != nil {
+		logger.DefaultLogger.Error("增加ZSet成员分数失败", "key:", req.Key, "member:", req.Member, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: 1,
+		Score:    cast.ToString(result),
+	})
+}
+
+// ZSetRemoveAction 删除ZSet成员 (ZREM)
+func (this *RedisController) ZSetRemoveAction(ctx *gin.Context) {
+	req := new(dto.RedisZSetRemoveRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" || len(req.Members) == 0 {
+		this.Error(ctx, fmt.Errorf("key和成员不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("删除ZSet成员", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "members:", len(req.Members))
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"ZREM", req.Key}
+	for _, member := range req.Members {
+		args = append(args, member)
+	}
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("删除ZSet成员失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisElementResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// newTombstone 生成一次性的占位值，用于按下标删除List元素
+func newTombstone() string {
+	return "__ev_redis_tombstone__:" + randomHex(16)
+}
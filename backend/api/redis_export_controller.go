@@ -0,0 +1,275 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	exportBatchSize     = 100    // 导出时每批并发读取的Key数量，每批写完后刷新输出
+	maxExportKeys       = 100000 // 导出时一次最多指定的Key数量
+	maxExportFailedKeys = 1000   // 汇总行中保留的读取失败Key数量上限
+)
+
+// ExportKeysAction 按Key列表或匹配条件导出为JSON Lines文件，每行包含key、type、ttl与value
+// value的编码与Key详情一致：hash为[field1, value1, ...]，zset为[member1, score1, ...]，stream为消息列表
+// 按批读取并边读边写，不在内存中缓存整个库；单个Key读取失败时跳过
+// 响应头发出后无法再返回错误，最后一行固定写入汇总行（summary为true），记录是否完整、失败的Key与中断原因
+func (this *RedisController) ExportKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisExportRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if len(req.Keys) > maxExportKeys {
+		this.Error(ctx, fmt.Errorf("一次最多指定%d个Key，更多的Key请按模式导出", maxExportKeys))
+		return
+	}
+	var pattern string
+	var filter func(key string) bool
+	if len(req.Keys) == 0 {
+		if pattern, filter, err = buildPatternMatcher(&req.RedisKeyPatternOptions); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+	}
+
+	logger.DefaultLogger.Debug("导出Redis Key", "conn_id:", req.EsConnect, "database:", req.Database,
+		"keys:", len(req.Keys), "pattern:", pattern)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	ctx.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=redis_db%d_%s.jsonl", req.Database, time.Now().Format("20060102150405")))
+
+	encoder := json.NewEncoder(ctx.Writer)
+	encoder.SetEscapeHTML(false)
+
+	summary := vo.RedisExportSummary{Summary: true, FailedKeys: []string{}}
+	writeBatch := func(keys []string) error {
+		lines, failedKeys := this.exportKeyLines(ctx, api, req.Database, keys)
+		summary.Failed += int64(len(failedKeys))
+		for _, key := range failedKeys {
+			if len(summary.FailedKeys) < maxExportFailedKeys {
+				summary.FailedKeys = append(summary.FailedKeys, key)
+			} else {
+				summary.FailedTruncated = true
+			}
+		}
+		for _, line := range lines {
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+			summary.Exported++
+		}
+		ctx.Writer.Flush()
+		return nil
+	}
+
+	if len(req.Keys) > 0 {
+		for start := 0; start < len(req.Keys) && err == nil; start += exportBatchSize {
+			end := start + exportBatchSize
+			if end > len(req.Keys) {
+				end = len(req.Keys)
+			}
+			err = writeBatch(req.Keys[start:end])
+		}
+	} else {
+		cursor := "0"
+		for err == nil {
+			var nextCursor string
+			var keys []string
+			nextCursor, keys, err = this.scanKeys(ctx, api, req.Database, cursor, pattern, maxScanCount)
+			if err != nil {
+				break
+			}
+
+			var matchedKeys []string
+			for _, key := range keys {
+				if filter == nil || filter(key) {
+					matchedKeys = append(matchedKeys, key)
+				}
+			}
+			for start := 0; start < len(matchedKeys) && err == nil; start += exportBatchSize {
+				end := start + exportBatchSize
+				if end > len(matchedKeys) {
+					end = len(matchedKeys)
+				}
+				err = writeBatch(matchedKeys[start:end])
+			}
+
+			cursor = nextCursor
+			if cursor == "0" {
+				break
+			}
+		}
+	}
+
+	// 响应头已发出，出错时只能中断输出，通过汇总行标记导出不完整
+	if err != nil {
+		logger.DefaultLogger.Error("导出Redis Key中断", "exported:", summary.Exported, "error:", err)
+		summary.Error = err.Error()
+	} else {
+		logger.DefaultLogger.Debug("导出Redis Key完成", "exported:", summary.Exported, "failed:", summary.Failed)
+	}
+	summary.Complete = err == nil && summary.Failed == 0
+	if err := encoder.Encode(summary); err != nil {
+		logger.DefaultLogger.Error("写入导出汇总行失败", "error:", err)
+		return
+	}
+	ctx.Writer.Flush()
+}
+
+// exportKeyLines 并发读取一批Key的类型、TTL与完整的值，保持输入顺序，返回成功读取的行与读取失败的Key
+func (this *RedisController) exportKeyLines(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string) ([]vo.RedisExportLine, []string) {
+	lines := make([]*vo.RedisExportLine, len(keys))
+	var failed []string
+	var mu sync.Mutex // 保护failed
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for i, key := range keys {
+		i, key := i, key // 避免闭包问题
+		g.Go(func() error {
+			line, err := this.exportKey(gctx, api, database, key)
+			if err != nil {
+				logger.DefaultLogger.Warn("导出Key失败", "key:", key, "error:", err)
+				mu.Lock()
+				failed = append(failed, key)
+				mu.Unlock()
+				return nil
+			}
+			lines[i] = line
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	result := make([]vo.RedisExportLine, 0, len(keys))
+	for _, line := range lines {
+		if line != nil {
+			result = append(result, *line)
+		}
+	}
+	return result, failed
+}
+
+// exportKey 读取单个Key的导出行，Key已不存在时返回nil
+func (this *RedisController) exportKey(ctx context.Context, api *ev_api.EvApiAdapter, database int, key string) (*vo.RedisExportLine, error) {
+	typeResult, err := this.executeRedisCommandWithRetry(ctx, api, database, "TYPE", key)
+	if err != nil {
+		return nil, err
+	}
+	keyType := cast.ToString(typeResult)
+	if keyType == "" || keyType == "none" {
+		return nil, nil
+	}
+
+	pttlResult, err := this.executeRedisCommandWithRetry(ctx, api, database, "PTTL", key)
+	if err != nil {
+		return nil, err
+	}
+	pttl := cast.ToInt64(pttlResult)
+	if pttl == -2 {
+		return nil, nil
+	}
+
+	value, err := this.readKeyValue(ctx, api, database, key, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 导入时TTL按秒设置，不足1秒的向上取整，避免被当作永不过期
+	ttl := int64(-1)
+	if pttl >= 0 {
+		ttl = (pttl + 999) / 1000
+	}
+	return &vo.RedisExportLine{Key: key, Type: keyType, TTL: ttl, Value: value}, nil
+}
+
+// readKeyValue 按页读取Key的全部元素，编码与Key详情一致；hash与set的SCAN结果跨轮可能重复，这里去重
+func (this *RedisController) readKeyValue(ctx context.Context, api *ev_api.EvApiAdapter, database int, key, keyType string) (interface{}, error) {
+	req := &dto.RedisKeyDetailRequest{Database: database, Key: key, PageSize: maxValuePageSize}
+
+	switch keyType {
+	case "string":
+		return this.executeRedisCommandWithRetry(ctx, api, database, "GET", key)
+	case "hash", "set":
+		step := 1
+		if keyType == "hash" {
+			step = 2
+		}
+		seen := map[string]bool{}
+		values := []interface{}{}
+		for {
+			page, nextCursor, err := this.scanKeyValues(ctx, api, req, keyType)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i+step <= len(page); i += step {
+				member := cast.ToString(page[i])
+				if seen[member] {
+					continue
+				}
+				seen[member] = true
+				values = append(values, page[i:i+step]...)
+			}
+			if nextCursor == "" {
+				return values, nil
+			}
+			req.Cursor = nextCursor
+		}
+	case "list", "zset":
+		total, err := this.keyElementCount(ctx, api, database, key, keyType)
+		if err != nil {
+			return nil, err
+		}
+		values := []interface{}{}
+		for {
+			page, nextCursor, err := this.rangeKeyValues(ctx, api, req, keyType, total)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, cast.ToSlice(page)...)
+			if nextCursor == "" {
+				return values, nil
+			}
+			req.Cursor = nextCursor
+		}
+	case "stream":
+		entries := []vo.RedisStreamEntry{}
+		start := "-"
+		for {
+			// 多取一条用于确定下一页的起始ID
+			result, err := this.executeRedisCommandWithRetry(ctx, api, database, "XRANGE", key, start, "+", "COUNT", maxValuePageSize+1)
+			if err != nil {
+				return nil, err
+			}
+			page := parseStreamEntries(result)
+			if len(page) <= maxValuePageSize {
+				return append(entries, page...), nil
+			}
+			entries = append(entries, page[:maxValuePageSize]...)
+			start = page[maxValuePageSize].Id
+		}
+	default:
+		return nil, fmt.Errorf("不支持导出的数据类型: %s", keyType)
+	}
+}
@@ -0,0 +1,388 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+// 热Key分析方式
+const (
+	HotKeyModeAuto    = "auto"    // LFU策略下使用lfu，否则使用monitor（需提供monitor_log）
+	HotKeyModeLFU     = "lfu"     // SCAN + OBJECT FREQ，需要LFU淘汰策略
+	HotKeyModeIdle    = "idle"    // SCAN + OBJECT IDLETIME，按最近访问时间排序，不代表访问频率，需显式指定
+	HotKeyModeMonitor = "monitor" // 解析redis-cli monitor采集的输出
+)
+
+// MONITOR会让连接持续推送命令流，基座的RedisExecCommand是一问一答的单次命令通道，插件无法在线采样
+const (
+	hotKeyMonitorUnsupported = "插件经由基座的单次命令通道(RedisExecCommand)访问Redis，无法执行需要持续读取连接的MONITOR命令，不支持在线MONITOR采样"
+	hotKeyMonitorHint        = "请用redis-cli monitor采集一段时间（如timeout 10 redis-cli monitor > monitor.log）后以monitor模式上传"
+)
+
+// monitorLineRegexp 匹配MONITOR输出行：1339518083.107412 [0 127.0.0.1:60866] "get" "foo"
+var monitorLineRegexp = regexp.MustCompile(`^\d+\.\d+ \[(\d+) [^\]]*\] (.*)$`)
+
+// 不含key的命令
+var monitorKeylessCommands = map[string]bool{
+	"ping": true, "info": true, "select": true, "auth": true, "client": true, "config": true,
+	"scan": true, "dbsize": true, "flushdb": true, "flushall": true, "multi": true, "exec": true,
+	"discard": true, "slowlog": true, "command": true, "hello": true, "time": true, "echo": true,
+	"quit": true, "monitor": true, "subscribe": true, "psubscribe": true, "unsubscribe": true,
+	"punsubscribe": true, "publish": true, "script": true, "memory": true, "latency": true,
+	"cluster": true, "readonly": true, "readwrite": true, "role": true, "lastsave": true,
+	"save": true, "bgsave": true, "bgrewriteaof": true, "randomkey": true, "unwatch": true,
+}
+
+// 所有参数都是key的命令
+var monitorAllKeysCommands = map[string]bool{
+	"del": true, "unlink": true, "exists": true, "touch": true, "mget": true, "watch": true,
+	"sinter": true, "sunion": true, "sdiff": true, "sinterstore": true, "sunionstore": true,
+	"sdiffstore": true, "pfcount": true, "pfmerge": true,
+}
+
+// 前两个参数是key的命令
+var monitorTwoKeysCommands = map[string]bool{
+	"rename": true, "renamenx": true, "rpoplpush": true, "lmove": true, "smove": true, "copy": true,
+}
+
+// 第一个参数是子命令、第二个参数是key的命令
+var monitorSubcommandKeyCommands = map[string]bool{
+	"object": true, "xinfo": true, "xgroup": true,
+}
+
+// GetHotKeysAction 热Key分析 - LFU策略下使用OBJECT FREQ，否则解析上传的MONITOR输出，也可显式指定OBJECT IDLETIME采样
+func (this *RedisController) GetHotKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisHotKeysRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 设置默认值
+	if req.Mode == "" {
+		req.Mode = HotKeyModeAuto
+	}
+	if req.Pattern == "" {
+		req.Pattern = "*"
+	}
+	if req.Count <= 0 {
+		req.Count = 10000
+	}
+	if req.Cursor == "" {
+		req.Cursor = "0"
+	}
+	if req.TopN <= 0 {
+		req.TopN = 50
+	}
+
+	logger.DefaultLogger.Debug("开始Redis热Key分析",
+		"conn_id:", req.EsConnect,
+		"database:", req.Database,
+		"mode:", req.Mode,
+		"cursor:", req.Cursor)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	policy, err := this.configGet(ctx, api, req.Database, "maxmemory-policy")
+	if err != nil {
+		logger.DefaultLogger.Warn("获取maxmemory-policy失败", "error:", err)
+	}
+
+	mode := req.Mode
+	notice := ""
+	if mode == HotKeyModeAuto {
+		switch {
+		case strings.Contains(policy, "lfu"):
+			mode = HotKeyModeLFU
+		case strings.TrimSpace(req.MonitorLog) != "":
+			mode = HotKeyModeMonitor
+		default:
+			// 非LFU策略应退化为MONITOR采样，不能静默替换为其他指标
+			this.Error(ctx, fmt.Errorf("当前maxmemory-policy为%s，不是LFU策略，无法使用OBJECT FREQ；%s；%s", policy, hotKeyMonitorUnsupported, hotKeyMonitorHint))
+			return
+		}
+	}
+	switch mode {
+	case HotKeyModeIdle:
+		notice = "idle模式按OBJECT IDLETIME排序，反映的是最近访问时间而不是访问频率，也没有按命令的调用统计"
+	case HotKeyModeMonitor:
+		notice = "结果来自上传的MONITOR输出；" + hotKeyMonitorUnsupported
+	}
+
+	resp := vo.RedisHotKeysResponse{
+		Mode:            mode,
+		MaxmemoryPolicy: policy,
+		NextCursor:      "0",
+		Notice:          notice,
+	}
+
+	switch mode {
+	case HotKeyModeLFU, HotKeyModeIdle:
+		if mode == HotKeyModeLFU && policy != "" && !strings.Contains(policy, "lfu") {
+			this.Error(ctx, fmt.Errorf("当前maxmemory-policy为%s，OBJECT FREQ需要LFU淘汰策略", policy))
+			return
+		}
+
+		keys, nextCursor, err := this.scanKeysPage(ctx, api, req.Database, req.Cursor, req.Pattern, req.Count, nil)
+		if err != nil {
+			logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+			this.Error(ctx, err)
+			return
+		}
+
+		hotKeys, err := this.objectHotKeys(ctx, api, req.Database, keys, mode)
+		if err != nil {
+			this.Error(ctx, err)
+			return
+		}
+		if len(hotKeys) > req.TopN {
+			hotKeys = hotKeys[:req.TopN]
+		}
+
+		resp.Keys = hotKeys
+		resp.ScannedKeys = len(keys)
+		resp.NextCursor = nextCursor
+	case HotKeyModeMonitor:
+		if strings.TrimSpace(req.MonitorLog) == "" {
+			this.Error(ctx, fmt.Errorf("monitor模式需要提供redis-cli monitor采集到的输出"))
+			return
+		}
+
+		hotKeys := parseMonitorHotKeys(req.MonitorLog, req.Database)
+		resp.ScannedKeys = len(hotKeys)
+		if len(hotKeys) > req.TopN {
+			hotKeys = hotKeys[:req.TopN]
+		}
+		resp.Keys = hotKeys
+	default:
+		this.Error(ctx, fmt.Errorf("不支持的分析方式: %s", req.Mode))
+		return
+	}
+
+	this.Success(ctx, response.SearchSuccess, resp)
+}
+
+// objectHotKeys 并发执行OBJECT FREQ/IDLETIME并按热度排序
+func (this *RedisController) objectHotKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string, mode string) ([]vo.RedisHotKeyInfo, error) {
+	subCommand := "IDLETIME"
+	if mode == HotKeyModeLFU {
+		subCommand = "FREQ"
+	}
+
+	hotKeys := make([]vo.RedisHotKeyInfo, 0, len(keys))
+	var mu sync.Mutex // 保护共享数据
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, "OBJECT", subCommand, key)
+			if err != nil {
+				// LFU策略未开启时OBJECT FREQ对每个key都会失败，直接终止
+				if strings.Contains(err.Error(), "LFU") {
+					return fmt.Errorf("OBJECT FREQ执行失败: %w", err)
+				}
+				logger.DefaultLogger.Debug("OBJECT命令执行失败", "key:", key, "error:", err)
+				return nil
+			}
+			if result == nil {
+				return nil // key已被删除
+			}
+
+			info := vo.RedisHotKeyInfo{Key: key}
+			if mode == HotKeyModeLFU {
+				info.Freq = cast.ToInt64(result)
+			} else {
+				info.IdleSeconds = cast.ToInt64(result)
+			}
+
+			mu.Lock()
+			hotKeys = append(hotKeys, info)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hotKeys, func(i, k int) bool {
+		if mode == HotKeyModeLFU {
+			return hotKeys[i].Freq > hotKeys[k].Freq
+		}
+		return hotKeys[i].IdleSeconds < hotKeys[k].IdleSeconds
+	})
+
+	return hotKeys, nil
+}
+
+// parseMonitorHotKeys 解析MONITOR输出，统计指定数据库中每个key的命令调用次数
+func parseMonitorHotKeys(monitorLog string, database int) []vo.RedisHotKeyInfo {
+	stats := map[string]*vo.RedisHotKeyInfo{}
+
+	scanner := bufio.NewScanner(strings.NewReader(monitorLog))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		match := monitorLineRegexp.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil || cast.ToInt(match[1]) != database {
+			continue
+		}
+
+		args := parseMonitorArgs(match[2])
+		if len(args) == 0 {
+			continue
+		}
+
+		command := strings.ToLower(args[0])
+		for _, key := range monitorCommandKeys(command, args[1:]) {
+			info, ok := stats[key]
+			if !ok {
+				info = &vo.RedisHotKeyInfo{Key: key, Commands: map[string]int64{}}
+				stats[key] = info
+			}
+			info.Calls++
+			info.Commands[command]++
+		}
+	}
+
+	hotKeys := make([]vo.RedisHotKeyInfo, 0, len(stats))
+	for _, info := range stats {
+		hotKeys = append(hotKeys, *info)
+	}
+	sort.Slice(hotKeys, func(i, k int) bool {
+		if hotKeys[i].Calls != hotKeys[k].Calls {
+			return hotKeys[i].Calls > hotKeys[k].Calls
+		}
+		return hotKeys[i].Key < hotKeys[k].Key
+	})
+	return hotKeys
+}
+
+// monitorCommandKeys 根据命令提取参数中的key
+func monitorCommandKeys(command string, args []string) []string {
+	if len(args) == 0 || monitorKeylessCommands[command] {
+		return nil
+	}
+
+	switch {
+	case monitorAllKeysCommands[command]:
+		return args
+	case monitorTwoKeysCommands[command]:
+		if len(args) >= 2 {
+			return args[:2]
+		}
+		return args
+	case monitorSubcommandKeyCommands[command]:
+		// HELP等子命令不带key
+		if len(args) < 2 {
+			return nil
+		}
+		return args[1:2]
+	case command == "bitop":
+		// 第一个参数是操作符，之后依次是目标key与源key
+		return args[1:]
+	case command == "xread" || command == "xreadgroup":
+		// STREAMS之后前一半是key，后一半是对应的ID
+		for i, arg := range args {
+			if strings.EqualFold(arg, "streams") {
+				rest := args[i+1:]
+				if len(rest) == 0 || len(rest)%2 != 0 {
+					return nil
+				}
+				return rest[:len(rest)/2]
+			}
+		}
+		return nil
+	case command == "mset" || command == "msetnx":
+		var keys []string
+		for i := 0; i < len(args); i += 2 {
+			keys = append(keys, args[i])
+		}
+		return keys
+	case command == "blpop" || command == "brpop" || command == "bzpopmin" || command == "bzpopmax":
+		// 最后一个参数是超时时间
+		return args[:len(args)-1]
+	case command == "eval" || command == "evalsha" || command == "eval_ro" || command == "evalsha_ro" ||
+		command == "fcall" || command == "fcall_ro":
+		if len(args) < 2 {
+			return nil
+		}
+		numKeys, err := strconv.Atoi(args[1])
+		if err != nil || numKeys <= 0 || len(args) < 2+numKeys {
+			return nil
+		}
+		return args[2 : 2+numKeys]
+	default:
+		return args[:1]
+	}
+}
+
+// parseMonitorArgs 解析MONITOR输出中以双引号包裹、以sdscatrepr方式转义的参数列表
+func parseMonitorArgs(line string) []string {
+	var args []string
+	for i := 0; i < len(line); {
+		if line[i] != '"' {
+			i++
+			continue
+		}
+
+		var b strings.Builder
+		i++
+		for i < len(line) && line[i] != '"' {
+			if line[i] == '\\' && i+1 < len(line) {
+				i++
+				switch line[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				case 'a':
+					b.WriteByte('\a')
+				case 'b':
+					b.WriteByte('\b')
+				case 'x':
+					if i+2 < len(line) {
+						if v, err := strconv.ParseUint(line[i+1:i+3], 16, 8); err == nil {
+							b.WriteByte(byte(v))
+							i += 2
+							break
+						}
+					}
+					b.WriteByte('x')
+				default:
+					b.WriteByte(line[i])
+				}
+				i++
+				continue
+			}
+			b.WriteByte(line[i])
+			i++
+		}
+		args = append(args, b.String())
+		i++ // 跳过结尾的双引号
+	}
+	return args
+}
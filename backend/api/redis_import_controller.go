@@ -0,0 +1,378 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/my_error"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+// 导入时目标Key已存在的处理策略
+const (
+	ImportPolicySkip      = "skip"      // 跳过已存在的Key
+	ImportPolicyOverwrite = "overwrite" // 覆盖已存在的Key
+	ImportPolicyFail      = "fail"      // 遇到已存在的Key时终止导入
+)
+
+const (
+	maxImportBytes  = 64 * 1024 * 1024 // 导入内容的大小上限
+	importBatchSize = 100              // 每批并发写入的行数
+	maxImportErrors = 1000             // 结果中保留的错误明细数量上限
+)
+
+// importContents 等待导入任务读取的文件内容，以任务参数中的content_id为key
+// 文件内容可能很大，不随任务参数落库，因此导入任务不支持断点续跑
+var importContents sync.Map
+
+// importLine 解析后的一行导入数据
+type importLine struct {
+	line int
+	req  *dto.RedisSetKeyRequest
+}
+
+// ImportKeysAction 导入JSON Lines文件（key、type、ttl、value，与导出格式一致）
+// dry_run时同步校验并统计冲突，不写入数据；否则启动后台任务，通过SetKeyAction相同的写入逻辑逐行写入
+func (this *RedisController) ImportKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisImportRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if err := normalizeImportParams(&req.RedisImportParams); err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		this.Error(ctx, fmt.Errorf("导入内容不能为空"))
+		return
+	}
+	if len(req.Content) > maxImportBytes {
+		this.Error(ctx, fmt.Errorf("导入内容不能超过%dMB", maxImportBytes/1024/1024))
+		return
+	}
+
+	logger.DefaultLogger.Debug("导入Redis Key", "conn_id:", req.EsConnect, "database:", req.Database,
+		"policy:", req.Policy, "dry_run:", req.DryRun, "bytes:", len(req.Content))
+
+	if req.DryRun {
+		// 调用基座API
+		api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+		result, err := this.importKeys(ctx, nil, api, req.Database, req.Content, &req.RedisImportParams)
+		if err != nil {
+			this.Error(ctx, err)
+			return
+		}
+		this.Success(ctx, response.SearchSuccess, vo.RedisImportResponse{Result: result})
+		return
+	}
+
+	req.ContentId = randomHex(16)
+	params, err := json.Marshal(req.RedisImportParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	importContents.Store(req.ContentId, req.Content)
+	j, err := this.jobManager.Start(ctx, JobKindImport, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		importContents.Delete(req.ContentId)
+		logger.DefaultLogger.Error("启动导入任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	jobInfo := toJobInfo(j.Record())
+	this.Success(ctx, response.OperateSuccess, vo.RedisImportResponse{Job: &jobInfo})
+}
+
+// importJob 导入任务
+func (this *RedisController) importJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisImportParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if err := normalizeImportParams(params); err != nil {
+		return nil, err
+	}
+
+	content, ok := importContents.LoadAndDelete(params.ContentId)
+	if !ok {
+		return nil, fmt.Errorf("导入内容已失效，插件重启后需要重新上传")
+	}
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	return this.importKeys(ctx, j, api, j.Database, content.(string), params)
+}
+
+// importKeys 逐批解析并写入导入内容，j为nil时为dry run：只校验格式并统计冲突，不会因fail策略提前终止
+// 先并发检查本批Key是否已存在，再按策略写入；写入时skip/fail策略要求Key不存在，检查后被并发创建的Key同样按冲突处理
+func (this *RedisController) importKeys(ctx context.Context, j *job.Job, api *ev_api.EvApiAdapter, database int, content string, params *dto.RedisImportParams) (*vo.RedisImportResult, error) {
+	dryRun := j == nil
+	result := &vo.RedisImportResult{
+		Policy: params.Policy,
+		DryRun: dryRun,
+		Errors: []vo.RedisImportLineError{},
+	}
+	var mu sync.Mutex // 保护result
+
+	addError := func(line int, key string, err error) {
+		result.Failed++
+		if len(result.Errors) < maxImportErrors {
+			result.Errors = append(result.Errors, vo.RedisImportLineError{Line: line, Key: key, Error: err.Error()})
+		} else {
+			result.ErrorsTruncated = true
+		}
+	}
+
+	if j != nil {
+		j.SetTotal(int64(strings.Count(content, "\n") + 1))
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxImportBytes)
+
+	lineNo := 0
+	var batch []importLine
+	flush := func() (bool, error) {
+		defer func() { batch = batch[:0] }()
+		if len(batch) == 0 {
+			return true, nil
+		}
+
+		exists, err := this.importKeysExist(ctx, api, database, batch)
+		if err != nil {
+			return false, err
+		}
+
+		var conflicts []importLine
+		var writes []importLine
+		for i, item := range batch {
+			if exists[i] {
+				conflicts = append(conflicts, item)
+				if params.Policy != ImportPolicyOverwrite {
+					continue
+				}
+			}
+			// skip/fail策略写入时要求Key不存在
+			if params.Policy != ImportPolicyOverwrite {
+				item.req.Version = keyVersionNone
+			}
+			writes = append(writes, item)
+		}
+		result.Conflicts += int64(len(conflicts))
+
+		if params.Policy == ImportPolicyFail && len(conflicts) > 0 {
+			if result.StoppedAtLine == 0 {
+				result.StoppedAtLine = conflicts[0].line
+			}
+			for _, item := range conflicts {
+				addError(item.line, item.req.Key, fmt.Errorf("Key已存在"))
+			}
+			if !dryRun {
+				// 本批不写入任何数据，终止在第一个冲突的行
+				return false, nil
+			}
+		}
+		if params.Policy == ImportPolicySkip {
+			result.Skipped += int64(len(conflicts))
+		}
+		if dryRun {
+			result.Imported += int64(len(writes))
+			return true, nil
+		}
+
+		// 同一批中重复出现的Key按出现顺序分轮写入，每轮内的Key互不相同，保证结果与按文件顺序逐行写入一致
+		var rounds [][]importLine
+		occurrences := make(map[string]int, len(writes))
+		for _, item := range writes {
+			n := occurrences[item.req.Key]
+			occurrences[item.req.Key] = n + 1
+			if n == len(rounds) {
+				rounds = append(rounds, nil)
+			}
+			rounds[n] = append(rounds[n], item)
+		}
+
+		stopped := false
+		for _, round := range rounds {
+			if stopped || ctx.Err() != nil {
+				break
+			}
+
+			g, gctx := errgroup.WithContext(ctx)
+			g.SetLimit(memoryAnalysisConcurrency)
+			for _, item := range round {
+				item := item // 避免闭包问题
+				g.Go(func() error {
+					err := this.replaceKey(gctx, api, item.req)
+
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						result.Imported++
+					case isImportConflict(err) && params.Policy == ImportPolicySkip:
+						result.Conflicts++
+						result.Skipped++
+					case isImportConflict(err):
+						result.Conflicts++
+						stopped = true
+						if result.StoppedAtLine == 0 || item.line < result.StoppedAtLine {
+							result.StoppedAtLine = item.line
+						}
+						addError(item.line, item.req.Key, fmt.Errorf("Key已存在"))
+					default:
+						addError(item.line, item.req.Key, err)
+					}
+					return nil
+				})
+			}
+			_ = g.Wait()
+		}
+
+		if j != nil {
+			j.AddProcessed(int64(len(batch)))
+			j.SetMessage(fmt.Sprintf("已处理%d行，导入%d个Key，跳过%d个，失败%d个", lineNo, result.Imported, result.Skipped, result.Failed))
+		}
+		return !stopped, ctx.Err()
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			if j != nil {
+				j.AddProcessed(1)
+			}
+			continue
+		}
+		// 导出文件的汇总行不是Key，只记录导出是否完整
+		if summary, ok := parseExportSummary(text); ok {
+			if !summary.Complete {
+				result.SourceIncomplete = true
+			}
+			if j != nil {
+				j.AddProcessed(1)
+			}
+			continue
+		}
+		result.Lines++
+
+		item, err := parseImportLine(text, database)
+		if err != nil {
+			addError(lineNo, "", err)
+			if j != nil {
+				j.AddProcessed(1)
+				j.AddFailed(1)
+			}
+			continue
+		}
+		item.line = lineNo
+		batch = append(batch, item)
+
+		if len(batch) >= importBatchSize {
+			if proceed, err := flush(); err != nil || !proceed {
+				return result, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		addError(lineNo+1, "", fmt.Errorf("读取导入内容失败: %w", err))
+		return result, nil
+	}
+
+	_, err := flush()
+	return result, err
+}
+
+// importKeysExist 并发检查一批Key是否已存在
+func (this *RedisController) importKeysExist(ctx context.Context, api *ev_api.EvApiAdapter, database int, batch []importLine) ([]bool, error) {
+	exists := make([]bool, len(batch))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+	for i, item := range batch {
+		i, item := i, item // 避免闭包问题
+		g.Go(func() error {
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, "EXISTS", item.req.Key)
+			if err != nil {
+				return fmt.Errorf("检查Key是否存在失败: %w", err)
+			}
+			exists[i] = cast.ToInt64(result) == 1
+			return nil
+		})
+	}
+	return exists, g.Wait()
+}
+
+// parseExportSummary 解析导出文件结尾的汇总行，不是汇总行时返回false
+func parseExportSummary(text string) (*vo.RedisExportSummary, bool) {
+	summary := new(vo.RedisExportSummary)
+	if err := json.Unmarshal([]byte(text), summary); err != nil || !summary.Summary {
+		return nil, false
+	}
+	return summary, true
+}
+
+// parseImportLine 解析一行导入数据并校验类型与值的格式
+func parseImportLine(text string, database int) (importLine, error) {
+	line := new(vo.RedisExportLine)
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(line); err != nil {
+		return importLine{}, fmt.Errorf("JSON格式错误: %w", err)
+	}
+	if line.Key == "" {
+		return importLine{}, fmt.Errorf("key不能为空")
+	}
+
+	req := &dto.RedisSetKeyRequest{
+		Database: database,
+		Key:      line.Key,
+		Type:     line.Type,
+		TTL:      line.TTL,
+		Value:    line.Value,
+	}
+	if _, err := replaceKeyPayload(req); err != nil {
+		return importLine{}, fmt.Errorf("Key %s: %w", line.Key, err)
+	}
+	return importLine{req: req}, nil
+}
+
+// isImportConflict 写入时Key已被并发创建
+func isImportConflict(err error) bool {
+	myErr, ok := err.(*my_error.MyError)
+	return ok && myErr.Code() == my_error.KeyVersionConflict
+}
+
+// normalizeImportParams 校验导入策略，并按策略设置写入时的版本号要求
+func normalizeImportParams(params *dto.RedisImportParams) error {
+	params.Policy = strings.ToLower(params.Policy)
+	switch params.Policy {
+	case "":
+		params.Policy = ImportPolicySkip
+	case ImportPolicySkip, ImportPolicyOverwrite, ImportPolicyFail:
+	default:
+		return fmt.Errorf("不支持的冲突策略: %s", params.Policy)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"ev-plugin/backend/vo"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// infoFlatExcludedSections 不放入扁平info字典的分段：条目数量随命令/错误种类增长，且已有对应的结构化字段
+var infoFlatExcludedSections = map[string]bool{
+	"commandstats": true,
+	"errorstats":   true,
+	"latencystats": true,
+}
+
+// parseInfoSections 按"# Section"标题解析INFO输出，分段名统一为小写
+func parseInfoSections(output string) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	current := "default"
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			current = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if sections[current] == nil {
+			sections[current] = make(map[string]string)
+		}
+		sections[current][strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return sections
+}
+
+// parseInfoFields 解析INFO中"k1=v1,k2=v2"形式的复合值
+func parseInfoFields(value string) map[string]string {
+	fields := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 2 {
+			fields[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return fields
+}
+
+// infoBool 将INFO中的0/1、yes/no等取值转换为布尔值
+func infoBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "yes", "true", "on":
+		return true
+	}
+	return false
+}
+
+// buildInfoResponse 将按分段解析的INFO信息填充为结构化响应，并计算派生指标
+func buildInfoResponse(sections map[string]map[string]string) vo.RedisInfoResponse {
+	resp := vo.RedisInfoResponse{
+		Info:     make(map[string]string),
+		Sections: sections,
+	}
+	for name, fields := range sections {
+		if infoFlatExcludedSections[name] {
+			continue
+		}
+		for k, v := range fields {
+			resp.Info[k] = v
+		}
+	}
+
+	server := sections["server"]
+	resp.Server = vo.RedisInfoServer{
+		RedisVersion:    server["redis_version"],
+		RedisMode:       server["redis_mode"],
+		Os:              server["os"],
+		ArchBits:        cast.ToInt(server["arch_bits"]),
+		ProcessId:       cast.ToInt64(server["process_id"]),
+		TcpPort:         cast.ToInt(server["tcp_port"]),
+		UptimeInSeconds: cast.ToInt64(server["uptime_in_seconds"]),
+		UptimeInDays:    cast.ToInt64(server["uptime_in_days"]),
+		Hz:              cast.ToInt(server["hz"]),
+		ConfigFile:      server["config_file"],
+	}
+
+	clients := sections["clients"]
+	resp.Clients = vo.RedisInfoClients{
+		ConnectedClients: cast.ToInt64(clients["connected_clients"]),
+		BlockedClients:   cast.ToInt64(clients["blocked_clients"]),
+		MaxClients:       cast.ToInt64(clients["maxclients"]),
+		TrackingClients:  cast.ToInt64(clients["tracking_clients"]),
+	}
+
+	memory := sections["memory"]
+	resp.Memory = vo.RedisInfoMemory{
+		UsedMemory:            cast.ToInt64(memory["used_memory"]),
+		UsedMemoryHuman:       memory["used_memory_human"],
+		UsedMemoryRss:         cast.ToInt64(memory["used_memory_rss"]),
+		UsedMemoryPeak:        cast.ToInt64(memory["used_memory_peak"]),
+		UsedMemoryPeakHuman:   memory["used_memory_peak_human"],
+		UsedMemoryLua:         cast.ToInt64(memory["used_memory_lua"]),
+		TotalSystemMemory:     cast.ToInt64(memory["total_system_memory"]),
+		MaxMemory:             cast.ToInt64(memory["maxmemory"]),
+		MaxMemoryHuman:        memory["maxmemory_human"],
+		MaxMemoryPolicy:       memory["maxmemory_policy"],
+		MemFragmentationRatio: cast.ToFloat64(memory["mem_fragmentation_ratio"]),
+		MemAllocator:          memory["mem_allocator"],
+	}
+
+	persistence := sections["persistence"]
+	resp.Persistence = vo.RedisInfoPersistence{
+		Loading:                 infoBool(persistence["loading"]),
+		RdbChangesSinceLastSave: cast.ToInt64(persistence["rdb_changes_since_last_save"]),
+		RdbBgsaveInProgress:     infoBool(persistence["rdb_bgsave_in_progress"]),
+		RdbLastSaveTime:         cast.ToInt64(persistence["rdb_last_save_time"]),
+		RdbLastBgsaveStatus:     persistence["rdb_last_bgsave_status"],
+		AofEnabled:              infoBool(persistence["aof_enabled"]),
+		AofRewriteInProgress:    infoBool(persistence["aof_rewrite_in_progress"]),
+		AofLastBgrewriteStatus:  persistence["aof_last_bgrewrite_status"],
+		AofLastWriteStatus:      persistence["aof_last_write_status"],
+	}
+
+	stats := sections["stats"]
+	resp.Stats = vo.RedisInfoStats{
+		TotalConnectionsReceived: cast.ToInt64(stats["total_connections_received"]),
+		TotalCommandsProcessed:   cast.ToInt64(stats["total_commands_processed"]),
+		InstantaneousOpsPerSec:   cast.ToInt64(stats["instantaneous_ops_per_sec"]),
+		TotalNetInputBytes:       cast.ToInt64(stats["total_net_input_bytes"]),
+		TotalNetOutputBytes:      cast.ToInt64(stats["total_net_output_bytes"]),
+		InstantaneousInputKbps:   cast.ToFloat64(stats["instantaneous_input_kbps"]),
+		InstantaneousOutputKbps:  cast.ToFloat64(stats["instantaneous_output_kbps"]),
+		RejectedConnections:      cast.ToInt64(stats["rejected_connections"]),
+		ExpiredKeys:              cast.ToInt64(stats["expired_keys"]),
+		EvictedKeys:              cast.ToInt64(stats["evicted_keys"]),
+		KeyspaceHits:             cast.ToInt64(stats["keyspace_hits"]),
+		KeyspaceMisses:           cast.ToInt64(stats["keyspace_misses"]),
+		PubsubChannels:           cast.ToInt64(stats["pubsub_channels"]),
+		PubsubPatterns:           cast.ToInt64(stats["pubsub_patterns"]),
+		LatestForkUsec:           cast.ToInt64(stats["latest_fork_usec"]),
+		TotalErrorReplies:        cast.ToInt64(stats["total_error_replies"]),
+	}
+
+	resp.Replication = parseInfoReplication(sections["replication"])
+
+	cpu := sections["cpu"]
+	resp.Cpu = vo.RedisInfoCpu{
+		UsedCpuSys:          cast.ToFloat64(cpu["used_cpu_sys"]),
+		UsedCpuUser:         cast.ToFloat64(cpu["used_cpu_user"]),
+		UsedCpuSysChildren:  cast.ToFloat64(cpu["used_cpu_sys_children"]),
+		UsedCpuUserChildren: cast.ToFloat64(cpu["used_cpu_user_children"]),
+	}
+
+	resp.Cluster = vo.RedisInfoCluster{
+		ClusterEnabled: infoBool(sections["cluster"]["cluster_enabled"]),
+	}
+
+	resp.Databases, resp.Keyspace = parseInfoKeyspace(sections["keyspace"])
+	resp.CommandStats = parseInfoCommandStats(sections["commandstats"])
+	resp.ErrorStats = parseInfoErrorStats(sections["errorstats"])
+	resp.Derived = deriveInfoMetrics(resp)
+
+	return resp
+}
+
+// parseInfoReplication 解析Replication分段，主节点下的slaveN条目解析为从节点列表
+func parseInfoReplication(replication map[string]string) vo.RedisInfoReplication {
+	result := vo.RedisInfoReplication{
+		Role:              replication["role"],
+		ConnectedSlaves:   cast.ToInt64(replication["connected_slaves"]),
+		MasterHost:        replication["master_host"],
+		MasterPort:        cast.ToInt(replication["master_port"]),
+		MasterLinkStatus:  replication["master_link_status"],
+		MasterReplOffset:  cast.ToInt64(replication["master_repl_offset"]),
+		ReplBacklogActive: infoBool(replication["repl_backlog_active"]),
+		ReplBacklogSize:   cast.ToInt64(replication["repl_backlog_size"]),
+		Replicas:          []vo.RedisInfoReplicaInfo{},
+	}
+
+	names := make([]string, 0)
+	for name := range replication {
+		if index := strings.TrimPrefix(name, "slave"); index != name && index != "" && cast.ToString(cast.ToInt(index)) == index {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return cast.ToInt(strings.TrimPrefix(names[i], "slave")) < cast.ToInt(strings.TrimPrefix(names[j], "slave"))
+	})
+	for _, name := range names {
+		fields := parseInfoFields(replication[name])
+		result.Replicas = append(result.Replicas, vo.RedisInfoReplicaInfo{
+			Ip:     fields["ip"],
+			Port:   cast.ToInt(fields["port"]),
+			State:  fields["state"],
+			Offset: cast.ToInt64(fields["offset"]),
+			Lag:    cast.ToInt64(fields["lag"]),
+		})
+	}
+	return result
+}
+
+// parseInfoKeyspace 解析Keyspace分段中的dbN条目，同时返回兼容旧版本接口的keyspace结构
+func parseInfoKeyspace(keyspace map[string]string) ([]vo.RedisDatabaseInfo, []map[string]interface{}) {
+	databases := []vo.RedisDatabaseInfo{}
+	legacy := []map[string]interface{}{}
+	for name, value := range keyspace {
+		index := strings.TrimPrefix(name, "db")
+		if index == name || index == "" {
+			continue
+		}
+		fields := parseInfoFields(value)
+		databases = append(databases, vo.RedisDatabaseInfo{
+			Database: cast.ToInt(index),
+			Keys:     cast.ToInt64(fields["keys"]),
+			Expires:  cast.ToInt64(fields["expires"]),
+			AvgTTL:   cast.ToInt64(fields["avg_ttl"]),
+		})
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		return databases[i].Database < databases[j].Database
+	})
+	for _, database := range databases {
+		fields := parseInfoFields(keyspace["db"+cast.ToString(database.Database)])
+		m := map[string]interface{}{"db": "db" + cast.ToString(database.Database)}
+		for k, v := range fields {
+			m[k] = v
+		}
+		legacy = append(legacy, m)
+	}
+	return databases, legacy
+}
+
+// parseInfoCommandStats 解析Commandstats分段中的cmdstat_xxx条目，按累计耗时倒序排列
+func parseInfoCommandStats(commandstats map[string]string) []vo.RedisCommandStat {
+	stats := []vo.RedisCommandStat{}
+	for name, value := range commandstats {
+		command := strings.TrimPrefix(name, "cmdstat_")
+		if command == name {
+			continue
+		}
+		fields := parseInfoFields(value)
+		stat := vo.RedisCommandStat{
+			Command:       command,
+			Calls:         cast.ToInt64(fields["calls"]),
+			Usec:          cast.ToInt64(fields["usec"]),
+			UsecPerCall:   cast.ToFloat64(fields["usec_per_call"]),
+			RejectedCalls: cast.ToInt64(fields["rejected_calls"]),
+			FailedCalls:   cast.ToInt64(fields["failed_calls"]),
+		}
+		if stat.UsecPerCall == 0 && stat.Calls > 0 {
+			stat.UsecPerCall = float64(stat.Usec) / float64(stat.Calls)
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Usec != stats[j].Usec {
+			return stats[i].Usec > stats[j].Usec
+		}
+		return stats[i].Command < stats[j].Command
+	})
+	return stats
+}
+
+// parseInfoErrorStats 解析Errorstats分段中的errorstat_xxx条目，按次数倒序排列
+func parseInfoErrorStats(errorstats map[string]string) []vo.RedisErrorStat {
+	stats := []vo.RedisErrorStat{}
+	for name, value := range errorstats {
+		prefix := strings.TrimPrefix(name, "errorstat_")
+		if prefix == name {
+			continue
+		}
+		stats = append(stats, vo.RedisErrorStat{
+			Error: prefix,
+			Count: cast.ToInt64(parseInfoFields(value)["count"]),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Error < stats[j].Error
+	})
+	return stats
+}
+
+// deriveInfoMetrics 计算命中率、碎片率、QPS等派生指标
+func deriveInfoMetrics(resp vo.RedisInfoResponse) vo.RedisInfoDerived {
+	derived := vo.RedisInfoDerived{
+		OpsPerSec:             resp.Stats.InstantaneousOpsPerSec,
+		MemFragmentationRatio: resp.Memory.MemFragmentationRatio,
+	}
+
+	if lookups := resp.Stats.KeyspaceHits + resp.Stats.KeyspaceMisses; lookups > 0 {
+		derived.HitRatio = float64(resp.Stats.KeyspaceHits) / float64(lookups)
+	}
+	// INFO中的碎片率只保留两位小数，能取到原始值时重新计算
+	if resp.Memory.UsedMemory > 0 && resp.Memory.UsedMemoryRss > 0 {
+		derived.MemFragmentationRatio = float64(resp.Memory.UsedMemoryRss) / float64(resp.Memory.UsedMemory)
+	}
+	if resp.Memory.MaxMemory > 0 {
+		derived.MemoryUsageRatio = float64(resp.Memory.UsedMemory) / float64(resp.Memory.MaxMemory)
+	}
+	if resp.Server.UptimeInSeconds > 0 {
+		derived.AvgOpsPerSec = float64(resp.Stats.TotalCommandsProcessed) / float64(resp.Server.UptimeInSeconds)
+	}
+	for _, database := range resp.Databases {
+		derived.TotalKeys += database.Keys
+		derived.TotalExpires += database.Expires
+	}
+
+	// calls只统计实际执行的命令，被拒绝的命令需要计入分母
+	var attempts, failed int64
+	for _, stat := range resp.CommandStats {
+		attempts += stat.Calls + stat.RejectedCalls
+		failed += stat.FailedCalls + stat.RejectedCalls
+	}
+	if attempts > 0 {
+		derived.FailedCallRatio = float64(failed) / float64(attempts)
+	}
+	return derived
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"sort"
+
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/spf13/cast"
+)
+
+// 后台任务类型
+const (
+	JobKindMemoryAnalysis  = "memory_analysis"  // 全量内存分析
+	JobKindBigKeys         = "big_keys"         // 大Key分析
+	JobKindBulkDelete      = "bulk_delete"      // 按模式批量删除（写操作）
+	JobKindBulkExpire      = "bulk_expire"      // 按模式批量设置过期时间（写操作）
+	JobKindTtlAnalysis     = "ttl_analysis"     // TTL分布分析
+	JobKindMigrate         = "migrate"          // 跨数据源迁移（写操作）
+	JobKindKeyspaceDiff    = "keyspace_diff"    // keyspace对比
+	JobKindImport          = "import"           // JSON Lines导入（写操作）
+	JobKindCommandImport   = "command_import"   // RESP/命令脚本导入（写操作）
+	JobKindSnapshot        = "snapshot"         // 创建Key快照（需先通过快照接口创建快照记录）
+	JobKindSnapshotRestore = "snapshot_restore" // 从快照恢复Key（写操作）
+	JobKindRdbAnalysis     = "rdb_analysis"     // RDB文件离线分析（需通过RDB分析接口启动）
+)
+
+// registerJobRunners 注册Redis相关的后台任务类型
+func (this *RedisController) registerJobRunners() {
+	this.jobManager.Register(JobKindMemoryAnalysis, false, this.memoryAnalysisJob)
+	this.jobManager.Register(JobKindBigKeys, false, this.bigKeysJob)
+	this.jobManager.RegisterResumable(JobKindBulkDelete, true, this.bulkDeleteJob)
+	this.jobManager.RegisterResumable(JobKindBulkExpire, true, this.bulkExpireJob)
+	this.jobManager.Register(JobKindTtlAnalysis, false, this.ttlAnalysisJob)
+	this.jobManager.RegisterResumable(JobKindMigrate, true, this.migrateJob)
+	this.jobManager.Register(JobKindKeyspaceDiff, false, this.keyspaceDiffJob)
+	this.jobManager.Register(JobKindImport, true, this.importJob)
+	this.jobManager.Register(JobKindCommandImport, true, this.commandImportJob)
+	this.jobManager.Register(JobKindSnapshot, true, this.snapshotJob)
+	this.jobManager.RegisterResumable(JobKindSnapshotRestore, true, this.snapshotRestoreJob)
+	this.jobManager.Register(JobKindRdbAnalysis, true, this.rdbAnalysisJob)
+}
+
+// memoryAnalysisJob 全量内存分析任务 - 按游标扫描整个keyspace，只保留汇总统计与TopN大Key
+func (this *RedisController) memoryAnalysisJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisMemoryAnalysisJobParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if params.Pattern == "" {
+		params.Pattern = "*"
+	}
+	if params.TopN <= 0 {
+		params.TopN = 100
+	}
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	if dbSizeResult, err := this.executeRedisCommandWithRetry(ctx, api, j.Database, "DBSIZE"); err == nil && dbSizeResult != nil {
+		j.SetTotal(cast.ToInt64(dbSizeResult))
+	}
+
+	result := &vo.RedisMemoryAnalysisJobResult{Pattern: params.Pattern}
+	typeStats := map[string]*vo.RedisTypeMemoryStat{}
+	var topKeys []vo.RedisKeyMemoryInfo
+
+	cursor := "0"
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		nextCursor, keys, err := this.scanKeys(ctx, api, j.Database, cursor, params.Pattern, maxScanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		keyMemoryInfos, totalSize, failed := this.analyzeKeysMemory(ctx, api, j.Database, keys)
+		for _, keyInfo := range keyMemoryInfos {
+			stat, ok := typeStats[keyInfo.Type]
+			if !ok {
+				stat = &vo.RedisTypeMemoryStat{Type: keyInfo.Type}
+				typeStats[keyInfo.Type] = stat
+			}
+			stat.Count++
+			stat.TotalSize += keyInfo.SizeBytes
+		}
+		result.ScannedKeys += int64(len(keys))
+		result.TotalSize += totalSize
+
+		// 定期截断，避免TopN候选集无限增长
+		topKeys = append(topKeys, keyMemoryInfos...)
+		if len(topKeys) > params.TopN*2 {
+			topKeys = topKeysBySize(topKeys, params.TopN)
+		}
+
+		j.AddProcessed(int64(len(keys)))
+		j.AddFailed(int64(failed))
+		j.SetMessage(fmt.Sprintf("已扫描%d个Key", result.ScannedKeys))
+
+		cursor = nextCursor
+		if cursor == "0" {
+			break
+		}
+	}
+
+	result.TopKeys = topKeysBySize(topKeys, params.TopN)
+	for _, stat := range typeStats {
+		result.TypeStats = append(result.TypeStats, *stat)
+	}
+	sort.Slice(result.TypeStats, func(i, k int) bool {
+		return result.TypeStats[i].TotalSize > result.TypeStats[k].TotalSize
+	})
+
+	return result, nil
+}
+
+// topKeysBySize 按大小倒序保留前n个key
+func topKeysBySize(keys []vo.RedisKeyMemoryInfo, n int) []vo.RedisKeyMemoryInfo {
+	sort.Slice(keys, func(i, k int) bool {
+		return keys[i].SizeBytes > keys[k].SizeBytes
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
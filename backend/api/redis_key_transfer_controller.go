@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/my_error"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+// Key复制/移动使用的方式
+const (
+	KeyTransferRename      = "RENAME"
+	KeyTransferRenameNX    = "RENAMENX"
+	KeyTransferCopy        = "COPY"
+	KeyTransferMove        = "MOVE"
+	KeyTransferDumpRestore = "DUMP/RESTORE"
+)
+
+// dumpRestoreScript 低版本Redis不支持COPY时，用DUMP/RESTORE复制Key并保留剩余TTL；脚本内SELECT不影响调用方连接
+// 删除源Key与写入目标Key在同一个脚本内完成，移动时不会出现两边都有或都没有的中间状态
+//
+// KEYS[1] 源Key，KEYS[2] 目标Key
+// ARGV[1] 源库，ARGV[2] 目标库，ARGV[3] 是否覆盖目标Key（1/0），ARGV[4] 是否删除源Key（1/0）
+// 返回1成功，0目标Key已存在，-1源Key不存在
+const dumpRestoreScript = `
+local value = redis.call('DUMP', KEYS[1])
+if not value then
+	return -1
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	ttl = 0
+end
+
+redis.call('SELECT', ARGV[2])
+if ARGV[3] == '1' then
+	redis.call('RESTORE', KEYS[2], ttl, value, 'REPLACE')
+else
+	if redis.call('EXISTS', KEYS[2]) == 1 then
+		return 0
+	end
+	redis.call('RESTORE', KEYS[2], ttl, value)
+end
+
+if ARGV[4] == '1' then
+	redis.call('SELECT', ARGV[1])
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`
+
+// RenameKeyAction 重命名Key - 默认使用RENAMENX，目标Key已存在时返回冲突错误，overwrite为true时使用RENAME覆盖
+func (this *RedisController) RenameKeyAction(ctx *gin.Context) {
+	req := new(dto.RedisRenameKeyRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Key == "" || req.NewKey == "" {
+		this.Error(ctx, fmt.Errorf("key和new_key不能为空"))
+		return
+	}
+	if req.Key == req.NewKey {
+		this.Error(ctx, fmt.Errorf("新Key名称与原Key相同"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("重命名Redis Key", "conn_id:", req.EsConnect, "database:", req.Database,
+		"key:", req.Key, "new_key:", req.NewKey, "overwrite:", req.Overwrite)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	method := KeyTransferRenameNX
+	if req.Overwrite {
+		method = KeyTransferRename
+	}
+	result, err := api.RedisExecCommand(ctx, req.Database, method, req.Key, req.NewKey)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			err = keyNotFoundError(req.Key)
+		}
+		logger.DefaultLogger.Error("重命名Redis Key失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+	if method == KeyTransferRenameNX && cast.ToInt64(result) == 0 {
+		this.Error(ctx, keyExistsError(req.NewKey, req.Database))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisKeyTransferResponse{
+		Key:      req.NewKey,
+		Database: req.Database,
+		Method:   method,
+	})
+}
+
+// CopyKeyAction 复制Key - Redis 6.2+使用COPY（支持DB与REPLACE），低版本退化为DUMP/RESTORE并保留TTL
+func (this *RedisController) CopyKeyAction(ctx *gin.Context) {
+	req := new(dto.RedisCopyKeyRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+	if req.NewKey == "" {
+		req.NewKey = req.Key
+	}
+	destDatabase := req.Database
+	if req.DestDatabase != nil {
+		destDatabase = *req.DestDatabase
+	}
+	if req.NewKey == req.Key && destDatabase == req.Database {
+		this.Error(ctx, fmt.Errorf("目标Key与源Key相同"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("复制Redis Key", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"dest_database:", destDatabase, "new_key:", req.NewKey, "replace:", req.Replace)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	useCopy := true
+	if version, err := this.serverVersion(ctx, api, req.Database); err != nil {
+		logger.DefaultLogger.Warn("获取Redis版本失败", "error:", err)
+	} else if !versionAtLeast(version, 6, 2) {
+		useCopy = false
+	}
+
+	method := KeyTransferCopy
+	var copied int64
+	if useCopy {
+		args := []interface{}{"COPY", req.Key, req.NewKey}
+		if destDatabase != req.Database {
+			args = append(args, "DB", destDatabase)
+		}
+		if req.Replace {
+			args = append(args, "REPLACE")
+		}
+		var result interface{}
+		result, err = api.RedisExecCommand(ctx, req.Database, args...)
+		if err != nil && strings.Contains(strings.ToLower(err.Error()), "unknown command") {
+			useCopy = false
+		} else if err == nil {
+			copied = cast.ToInt64(result)
+		}
+	}
+	if !useCopy {
+		method = KeyTransferDumpRestore
+		copied, err = this.dumpRestoreKey(ctx, api, req.Database, req.Key, destDatabase, req.NewKey, req.Replace, false)
+	}
+	if err != nil {
+		logger.DefaultLogger.Error("复制Redis Key失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	if copied != 1 {
+		this.Error(ctx, this.transferFailedError(ctx, api, req.Database, req.Key, destDatabase, req.NewKey, copied))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisKeyTransferResponse{
+		Key:      req.NewKey,
+		Database: destDatabase,
+		Method:   method,
+	})
+}
+
+// MoveKeyAction 将Key移动到其他库 - 默认使用MOVE，目标库已存在同名Key时返回冲突错误，replace为true时通过DUMP/RESTORE覆盖
+func (this *RedisController) MoveKeyAction(ctx *gin.Context) {
+	req := new(dto.RedisMoveKeyRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+	if req.DestDatabase == req.Database {
+		this.Error(ctx, fmt.Errorf("目标库与当前库相同"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("移动Redis Key", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"dest_database:", req.DestDatabase, "replace:", req.Replace)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	method := KeyTransferMove
+	var moved int64
+	if req.Replace {
+		// MOVE不支持覆盖目标Key
+		method = KeyTransferDumpRestore
+		moved, err = this.dumpRestoreKey(ctx, api, req.Database, req.Key, req.DestDatabase, req.Key, true, true)
+	} else {
+		var result interface{}
+		result, err = api.RedisExecCommand(ctx, req.Database, "MOVE", req.Key, req.DestDatabase)
+		moved = cast.ToInt64(result)
+	}
+	if err != nil {
+		logger.DefaultLogger.Error("移动Redis Key失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	if moved != 1 {
+		this.Error(ctx, this.transferFailedError(ctx, api, req.Database, req.Key, req.DestDatabase, req.Key, moved))
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisKeyTransferResponse{
+		Key:      req.Key,
+		Database: req.DestDatabase,
+		Method:   method,
+	})
+}
+
+// dumpRestoreKey 通过Lua脚本执行DUMP/RESTORE，返回值含义同dumpRestoreScript
+func (this *RedisController) dumpRestoreKey(ctx context.Context, api *ev_api.EvApiAdapter, database int, key string, destDatabase int, destKey string, replace, removeSource bool) (int64, error) {
+	result, err := api.RedisExecCommand(ctx, database, "EVAL", dumpRestoreScript, "2", key, destKey,
+		strconv.Itoa(database), strconv.Itoa(destDatabase), boolFlag(replace), boolFlag(removeSource))
+	if err != nil {
+		return 0, err
+	}
+	return cast.ToInt64(result), nil
+}
+
+// transferFailedError COPY/MOVE返回0时无法区分源Key不存在与目标Key已存在，需再检查一次源Key
+func (this *RedisController) transferFailedError(ctx context.Context, api *ev_api.EvApiAdapter, database int, key string, destDatabase int, destKey string, result int64) error {
+	if result == 0 {
+		exists, err := this.executeRedisCommandWithRetry(ctx, api, database, "EXISTS", key)
+		if err != nil || cast.ToInt64(exists) == 1 {
+			return keyExistsError(destKey, destDatabase)
+		}
+	}
+	return keyNotFoundError(key)
+}
+
+func keyNotFoundError(key string) error {
+	return my_error.NewError(fmt.Sprintf("Key %s 不存在", key), my_error.KeyNotFound)
+}
+
+func keyExistsError(key string, database int) error {
+	return my_error.NewError(fmt.Sprintf("db%d中已存在Key %s", database, key), my_error.KeyAlreadyExists)
+}
+
+func boolFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
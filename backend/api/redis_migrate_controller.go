@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxMigrateKeys     = 100000 // 迁移时一次最多指定的Key数量
+	maxMigrateFailures = 1000   // 迁移结果中保留的失败明细数量上限
+
+	maxMigrateKeyBytes = 4 * 1024 * 1024 // 单个Key的内存上限，超过时跳过并记为失败；十六进制编码需在脚本中完成，会阻塞Redis
+)
+
+// dumpKeyTooLargeError Key超过内存上限时DUMP脚本返回的错误信息
+const dumpKeyTooLargeError = "EV_KEY_TOO_LARGE"
+
+// 单个Key的迁移结果
+const (
+	migrateMigrated = "migrated" // 已写入目标
+	migrateSkipped  = "skipped"  // 目标已存在且未开启覆盖
+	migrateMissing  = "missing"  // 源Key已不存在
+)
+
+// hexTableLua 构建字节与十六进制之间的查找表，gsub使用表替换时在C中完成查找，避免每个字节调用一次Lua函数
+const hexTableLua = `
+local byteToHex, hexToByte = {}, {}
+for i = 0, 255 do
+	local c, h = string.char(i), string.format('%02x', i)
+	byteToHex[c] = h
+	hexToByte[h] = c
+end
+`
+
+// dumpHexScript 读取Key的DUMP序列化值与剩余TTL，序列化值是二进制数据，转成十六进制后再经基座接口传输
+// 编码的耗时与内存开销与Key大小成正比，ARGV[1]大于0时先用MEMORY USAGE检查，超过上限则不DUMP并返回错误
+// 返回{剩余毫秒数, 十六进制序列化值}，Key不存在时返回nil
+const dumpHexScript = hexTableLua + `
+local maxBytes = tonumber(ARGV[1])
+if maxBytes > 0 then
+	local memory = redis.call('MEMORY', 'USAGE', KEYS[1])
+	if memory and memory > maxBytes then
+		return redis.error_reply('` + dumpKeyTooLargeError + `')
+	end
+end
+local value = redis.call('DUMP', KEYS[1])
+if not value then
+	return nil
+end
+local hex = value:gsub('.', byteToHex)
+return {redis.call('PTTL', KEYS[1]), hex}
+`
+
+// restoreHexScript 将十六进制序列化值还原到目标Key
+// KEYS[1] 目标Key，ARGV[1] 十六进制序列化值，ARGV[2] TTL毫秒数（0表示不过期），ARGV[3] 是否覆盖（1/0）
+// 返回1成功，0目标Key已存在
+const restoreHexScript = hexTableLua + `
+local value = ARGV[1]:lower():gsub('..', hexToByte)
+if ARGV[3] == '1' then
+	redis.call('RESTORE', KEYS[1], ARGV[2], value, 'REPLACE')
+	return 1
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('RESTORE', KEYS[1], ARGV[2], value)
+return 1
+`
+
+// MigrateKeysAction 跨数据源迁移Key - 以后台任务的方式从当前连接DUMP并RESTORE到目标连接，保留TTL
+func (this *RedisController) MigrateKeysAction(ctx *gin.Context) {
+	req := new(dto.RedisMigrateRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.TargetConnect <= 0 {
+		this.Error(ctx, fmt.Errorf("请选择目标数据源"))
+		return
+	}
+	if req.TargetConnect == req.EsConnect && req.TargetDatabase == req.Database {
+		this.Error(ctx, fmt.Errorf("源与目标不能是同一个数据源的同一个库"))
+		return
+	}
+	if len(req.Keys) > maxMigrateKeys {
+		this.Error(ctx, fmt.Errorf("一次最多指定%d个Key，更多的Key请按模式迁移", maxMigrateKeys))
+		return
+	}
+	if len(req.Keys) == 0 {
+		if _, _, err := buildPatternMatcher(&req.RedisKeyPatternOptions); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+	}
+
+	params, err := json.Marshal(req.RedisMigrateParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("启动跨数据源迁移任务", "conn_id:", req.EsConnect, "database:", req.Database,
+		"target_connect:", req.TargetConnect, "target_database:", req.TargetDatabase,
+		"keys:", len(req.Keys), "pattern:", req.Pattern, "replace:", req.Replace)
+
+	j, err := this.jobManager.Start(ctx, JobKindMigrate, req.EsConnect, req.Database, util.GetEvUserID(ctx), params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动跨数据源迁移任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// migrateJob 跨数据源迁移任务 - 指定Key列表时按列表下标记录断点，按模式迁移时按SCAN游标记录断点
+func (this *RedisController) migrateJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisMigrateParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	batchSize, keysPerSecond := normalizeBulkRate(params.BatchSize, params.KeysPerSecond)
+
+	source := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+	target := ev_api.NewEvWrapApi(params.TargetConnect, j.UserId)
+
+	// 续跑时在原任务中断前的结果上继续累计，之前记录的失败明细不会丢失
+	result := &vo.RedisMigrateJobResult{}
+	if err := j.BindResult(result); err != nil {
+		return nil, err
+	}
+	result.TargetConnect = params.TargetConnect
+	result.TargetDatabase = params.TargetDatabase
+	if result.Failures == nil {
+		result.Failures = []vo.RedisMigrateFailure{}
+	}
+	j.TrackResult(result)
+
+	handle := func(batch []string) error {
+		this.migrateKeys(ctx, j, source, target, batch, params, result)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result.Matched += int64(len(batch))
+		j.SetMessage(fmt.Sprintf("已迁移%d个Key，跳过%d个，失败%d个", result.Migrated, result.Skipped, result.Failed))
+		return nil
+	}
+
+	if len(params.Keys) > 0 {
+		j.SetTotal(int64(len(params.Keys)))
+		start := cast.ToInt(j.Checkpoint())
+		for start < len(params.Keys) {
+			end := start + batchSize
+			if end > len(params.Keys) {
+				end = len(params.Keys)
+			}
+
+			if err := handle(params.Keys[start:end]); err != nil {
+				return nil, err
+			}
+			j.AddProcessed(int64(end - start))
+			j.SetCheckpoint(strconv.Itoa(end))
+
+			start = end
+			if start < len(params.Keys) {
+				if err := throttle(ctx, batchSize, keysPerSecond); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return result, nil
+	}
+
+	pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+	if err != nil {
+		return nil, err
+	}
+	result.Pattern = pattern
+	if params.Expected > 0 {
+		j.SetTotal(params.Expected)
+	}
+
+	scanned, _, err := this.scanMatchedBatches(ctx, j, source, pattern, filter, batchSize, keysPerSecond, handle)
+	result.Scanned += scanned
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// migrateKeys 并发迁移一批Key，逐个Key执行避免集群模式下多个Key不在同一slot，结果累计到result
+func (this *RedisController) migrateKeys(ctx context.Context, j *job.Job, source, target *ev_api.EvApiAdapter, keys []string, params *dto.RedisMigrateParams, result *vo.RedisMigrateJobResult) {
+	var mu sync.Mutex // 保护result
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			status, err := this.migrateKey(gctx, source, j.Database, target, params.TargetDatabase, key, params.Replace)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Debug("迁移Key失败", "key:", key, "error:", err)
+				result.Failed++
+				j.AddFailed(1)
+				if len(result.Failures) < maxMigrateFailures {
+					result.Failures = append(result.Failures, vo.RedisMigrateFailure{Key: key, Error: err.Error()})
+				} else {
+					result.FailuresTruncated = true
+				}
+				return nil
+			}
+			switch status {
+			case migrateMigrated:
+				result.Migrated++
+			case migrateSkipped:
+				result.Skipped++
+			case migrateMissing:
+				result.Missing++
+			}
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// migrateKey 从源库DUMP单个Key并RESTORE到目标库，保留剩余TTL
+func (this *RedisController) migrateKey(ctx context.Context, source *ev_api.EvApiAdapter, sourceDatabase int, target *ev_api.EvApiAdapter, targetDatabase int, key string, replace bool) (string, error) {
+	pttl, payload, err := this.dumpKeyHex(ctx, source, sourceDatabase, key, maxMigrateKeyBytes)
+	if err != nil {
+		return "", fmt.Errorf("DUMP失败: %w", err)
+	}
+	if payload == "" {
+		return migrateMissing, nil
+	}
+
+	restored, err := this.restoreKeyHex(ctx, target, targetDatabase, key, pttl, payload, replace)
+	if err != nil {
+		return "", fmt.Errorf("RESTORE失败: %w", err)
+	}
+	if !restored {
+		return migrateSkipped, nil
+	}
+	return migrateMigrated, nil
+}
+
+// dumpKeyHex 获取Key的十六进制DUMP序列化值与剩余TTL（毫秒，0表示不过期），Key不存在时payload为空
+// maxBytes大于0时，Key占用内存超过maxBytes则不DUMP并返回错误
+func (this *RedisController) dumpKeyHex(ctx context.Context, api *ev_api.EvApiAdapter, database int, key string, maxBytes int64) (int64, string, error) {
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, "EVAL", dumpHexScript, "1", key, strconv.FormatInt(maxBytes, 10))
+	if err != nil && strings.Contains(err.Error(), dumpKeyTooLargeError) {
+		return 0, "", fmt.Errorf("Key占用内存超过%dMB的上限", maxBytes/1024/1024)
+	}
+	if err != nil || result == nil {
+		return 0, "", err
+	}
+	reply := cast.ToSlice(result)
+	if len(reply) != 2 {
+		return 0, "", fmt.Errorf("DUMP脚本返回格式错误")
+	}
+	pttl := cast.ToInt64(reply[0])
+	if pttl < 0 {
+		pttl = 0
+	}
+	return pttl, cast.ToString(reply[1]), nil
+}
+
+// restoreKeyHex 将十六进制DUMP序列化值写入Key，未开启覆盖且Key已存在时返回false
+func (this *RedisController) restoreKeyHex(ctx context.Context, api *ev_api.EvApiAdapter, database int, key string, pttl int64, payload string, replace bool) (bool, error) {
+	result, err := api.RedisExecCommand(ctx, database, "EVAL", restoreHexScript, "1", key,
+		payload, strconv.FormatInt(pttl, 10), boolFlag(replace))
+	if err != nil {
+		return false, err
+	}
+	return cast.ToInt64(result) == 1, nil
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+)
+
+// 命名空间树的内存统计方式
+const (
+	NamespaceMemoryNone    = "none"    // 只统计数量
+	NamespaceMemorySampled = "sampled" // 每个节点采样部分key，按均值估算总大小
+	NamespaceMemoryExact   = "exact"   // 对每个key执行MEMORY USAGE
+)
+
+// maxNamespaceExactScanKeys exact模式下单次请求最多扫描的key数量，每个key都要执行MEMORY USAGE，
+// 超出部分通过nextCursor分多次请求统计，避免单个请求长时间占用
+const maxNamespaceExactScanKeys = 5000
+
+// maxNamespaceSampledKeys sampled模式下单次请求最多采样的key总数，按key数量从多到少为节点分配样本，
+// 超出后剩余节点不再执行MEMORY USAGE，按已采样key的平均大小估算
+const maxNamespaceSampledKeys = 2000
+
+// namespaceAggregate 命名空间节点的聚合中间态
+type namespaceAggregate struct {
+	node       *vo.RedisNamespaceNode
+	sampleKeys []string
+}
+
+// GetNamespaceTreeAction 按分隔符聚合key为前缀树，每次只返回prefix下一层的节点
+func (this *RedisController) GetNamespaceTreeAction(ctx *gin.Context) {
+	req := new(dto.RedisNamespaceTreeRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 设置默认值
+	if req.Delimiter == "" {
+		req.Delimiter = ":"
+	}
+	if req.MemoryMode == "" {
+		req.MemoryMode = NamespaceMemorySampled
+	}
+	if req.SampleSize <= 0 {
+		req.SampleSize = 20
+	}
+	if req.MaxScanKeys <= 0 {
+		req.MaxScanKeys = 100000
+	}
+	if req.MaxLeafKeys <= 0 {
+		req.MaxLeafKeys = 500
+	}
+	if req.Cursor == "" {
+		req.Cursor = "0"
+	}
+
+	switch req.MemoryMode {
+	case NamespaceMemoryNone, NamespaceMemorySampled:
+	case NamespaceMemoryExact:
+		if req.MaxScanKeys > maxNamespaceExactScanKeys {
+			req.MaxScanKeys = maxNamespaceExactScanKeys
+		}
+	default:
+		this.Error(ctx, fmt.Errorf("不支持的内存统计方式: %s", req.MemoryMode))
+		return
+	}
+
+	logger.DefaultLogger.Debug("获取Redis Key命名空间树",
+		"conn_id:", req.EsConnect,
+		"database:", req.Database,
+		"prefix:", req.Prefix,
+		"delimiter:", req.Delimiter,
+		"memory_mode:", req.MemoryMode)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	aggregates := map[string]*namespaceAggregate{}
+	var leafKeys []string
+	var leafCount, scannedKeys int64
+
+	pattern := escapeGlob(req.Prefix) + "*"
+	cursor := req.Cursor
+	for {
+		nextCursor, keys, err := this.scanKeys(ctx, api, req.Database, cursor, pattern, maxScanCount)
+		if err != nil {
+			logger.DefaultLogger.Error("SCAN命令执行失败", "error:", err)
+			this.Error(ctx, err)
+			return
+		}
+
+		for _, key := range keys {
+			rest := strings.TrimPrefix(key, req.Prefix)
+			idx := strings.Index(rest, req.Delimiter)
+			if idx < 0 {
+				// 直接位于该前缀下的叶子key
+				leafCount++
+				if len(leafKeys) < req.MaxLeafKeys {
+					leafKeys = append(leafKeys, key)
+				}
+				continue
+			}
+
+			name := rest[:idx]
+			agg, ok := aggregates[name]
+			if !ok {
+				agg = &namespaceAggregate{node: &vo.RedisNamespaceNode{
+					Name:          name,
+					FullPath:      req.Prefix + name + req.Delimiter,
+					TypeBreakdown: map[string]int64{},
+				}}
+				aggregates[name] = agg
+			}
+			agg.node.KeyCount++
+			if req.MemoryMode == NamespaceMemoryExact || len(agg.sampleKeys) < req.SampleSize {
+				agg.sampleKeys = append(agg.sampleKeys, key)
+			}
+		}
+
+		scannedKeys += int64(len(keys))
+		cursor = nextCursor
+		if cursor == "0" || scannedKeys >= int64(req.MaxScanKeys) {
+			break
+		}
+	}
+
+	// 按key数量倒序，采样名额优先分给大节点
+	ordered := make([]*namespaceAggregate, 0, len(aggregates))
+	for _, agg := range aggregates {
+		ordered = append(ordered, agg)
+	}
+	sort.Slice(ordered, func(i, k int) bool {
+		if ordered[i].node.KeyCount != ordered[k].node.KeyCount {
+			return ordered[i].node.KeyCount > ordered[k].node.KeyCount
+		}
+		return ordered[i].node.Name < ordered[k].node.Name
+	})
+
+	// 统计类型与内存
+	leafInfos := map[string]vo.RedisKeyMemoryInfo{}
+	sampleLimited := false
+	if req.MemoryMode != NamespaceMemoryNone {
+		budget := maxNamespaceSampledKeys
+		var sampledTotal, sampledSize int64
+		var unsampled []*namespaceAggregate
+		for _, agg := range ordered {
+			if req.MemoryMode == NamespaceMemorySampled {
+				if budget <= 0 {
+					unsampled = append(unsampled, agg)
+					continue
+				}
+				if len(agg.sampleKeys) > budget {
+					agg.sampleKeys = agg.sampleKeys[:budget]
+				}
+				budget -= len(agg.sampleKeys)
+			}
+			keyMemoryInfos, totalSize, _ := this.analyzeKeysMemory(ctx, api, req.Database, agg.sampleKeys)
+			sampledTotal += int64(len(keyMemoryInfos))
+			sampledSize += totalSize
+			for _, keyInfo := range keyMemoryInfos {
+				agg.node.TypeBreakdown[keyInfo.Type]++
+			}
+			agg.node.SampledKeys = int64(len(keyMemoryInfos))
+			agg.node.TotalSize = totalSize
+			if req.MemoryMode == NamespaceMemorySampled && agg.node.SampledKeys > 0 && agg.node.SampledKeys < agg.node.KeyCount {
+				agg.node.TotalSize = totalSize / agg.node.SampledKeys * agg.node.KeyCount
+				agg.node.TypeBreakdown = scaleTypeBreakdown(agg.node.TypeBreakdown, agg.node.SampledKeys, agg.node.KeyCount)
+			}
+		}
+
+		// 超出采样上限的节点只有数量，大小按已采样key的平均值估算
+		for _, agg := range unsampled {
+			agg.node.Estimated = true
+			if sampledTotal > 0 {
+				agg.node.TotalSize = sampledSize / sampledTotal * agg.node.KeyCount
+			}
+		}
+		sampleLimited = len(unsampled) > 0
+
+		keyMemoryInfos, _, _ := this.analyzeKeysMemory(ctx, api, req.Database, leafKeys)
+		for _, keyInfo := range keyMemoryInfos {
+			leafInfos[keyInfo.Key] = keyInfo
+		}
+	}
+
+	nodes := make([]vo.RedisNamespaceNode, 0, len(ordered)+len(leafKeys))
+	for _, agg := range ordered {
+		nodes = append(nodes, *agg.node)
+	}
+
+	sort.Strings(leafKeys)
+	for _, key := range leafKeys {
+		leaf := vo.RedisNamespaceNode{
+			Name:     strings.TrimPrefix(key, req.Prefix),
+			FullPath: key,
+			IsLeaf:   true,
+			KeyCount: 1,
+		}
+		if keyInfo, ok := leafInfos[key]; ok {
+			leaf.Type = keyInfo.Type
+			leaf.TotalSize = keyInfo.SizeBytes
+			leaf.SampledKeys = 1
+			leaf.TypeBreakdown = map[string]int64{keyInfo.Type: 1}
+		}
+		nodes = append(nodes, leaf)
+	}
+
+	logger.DefaultLogger.Debug("命名空间树统计完成",
+		"prefix:", req.Prefix,
+		"节点数:", len(aggregates),
+		"叶子Keys:", leafCount,
+		"扫描Keys:", scannedKeys,
+		"采样受限:", sampleLimited)
+
+	this.Success(ctx, response.SearchSuccess, vo.RedisNamespaceTreeResponse{
+		Prefix:        req.Prefix,
+		Delimiter:     req.Delimiter,
+		MemoryMode:    req.MemoryMode,
+		Nodes:         nodes,
+		LeafCount:     leafCount,
+		ScannedKeys:   scannedKeys,
+		NextCursor:    cursor,
+		Partial:       cursor != "0" || sampleLimited,
+		SampleLimited: sampleLimited,
+	})
+}
+
+// scaleTypeBreakdown 将采样得到的类型分布按比例放大到节点的key总数，舍入误差计入数量最多的类型，保证合计等于总数
+func scaleTypeBreakdown(breakdown map[string]int64, sampled, total int64) map[string]int64 {
+	scaled := make(map[string]int64, len(breakdown))
+	var sum int64
+	var largest string
+	for keyType, count := range breakdown {
+		scaled[keyType] = count * total / sampled
+		sum += scaled[keyType]
+		if largest == "" || count > breakdown[largest] || (count == breakdown[largest] && keyType < largest) {
+			largest = keyType
+		}
+	}
+	if largest != "" {
+		scaled[largest] += total - sum
+	}
+	return scaled
+}
@@ -0,0 +1,437 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/rdb"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxRdbPrefixDepth      = 5      // 前缀聚合的最大层数
+	maxRdbTrackedPrefixes  = 200000 // 统计的前缀数量上限，避免Key名高度分散时占用过多内存
+	rdbProgressInterval    = 10000  // 每解析多少个Key更新一次进度
+	rdbUploadFormFile      = "file"
+	rdbUploadFormParams    = "params"
+	rdbUploadFilePattern   = "ev-redis-rdb-*.rdb"
+	defaultRdbTopN         = 100
+	defaultRdbMaxPrefixes  = 500
+	defaultRdbPrefixDepth  = 2
+	defaultRdbKeyDelimiter = ":"
+)
+
+// rdbUploads 已上传、等待分析任务读取的RDB临时文件，按UploadId保存文件路径
+var rdbUploads sync.Map
+
+// RdbAnalysisAction 离线分析RDB文件 - 上传文件（multipart）或指定插件所在机器上的文件路径（JSON），
+// 以后台任务的方式在插件内解析，不访问Redis
+func (this *RedisController) RdbAnalysisAction(ctx *gin.Context) {
+	req := new(dto.RedisRdbAnalysisRequest)
+
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		if params := ctx.PostForm(rdbUploadFormParams); params != "" {
+			if err := json.Unmarshal([]byte(params), req); err != nil {
+				this.Error(ctx, fmt.Errorf("params格式错误: %w", err))
+				return
+			}
+		}
+		path, err := saveRdbUpload(ctx)
+		if err != nil {
+			this.Error(ctx, err)
+			return
+		}
+		req.Path = ""
+		req.UploadId = randomHex(16)
+		rdbUploads.Store(req.UploadId, path)
+	} else {
+		if err := ctx.BindJSON(req); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+		req.UploadId = ""
+		if err := checkRdbFile(req.Path); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+	}
+
+	params, err := json.Marshal(req.RedisRdbAnalysisParams)
+	if err != nil {
+		removeRdbUpload(req.UploadId)
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("启动RDB离线分析任务", "conn_id:", req.EsConnect, "path:", req.Path, "upload_id:", req.UploadId)
+
+	j, err := this.jobManager.Start(ctx, JobKindRdbAnalysis, req.EsConnect, 0, util.GetEvUserID(ctx), params)
+	if err != nil {
+		removeRdbUpload(req.UploadId)
+		logger.DefaultLogger.Error("启动RDB离线分析任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// saveRdbUpload 将上传的RDB文件保存到临时目录，返回文件路径
+func saveRdbUpload(ctx *gin.Context) (string, error) {
+	header, err := ctx.FormFile(rdbUploadFormFile)
+	if err != nil {
+		return "", fmt.Errorf("请上传RDB文件: %w", err)
+	}
+	src, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", rdbUploadFilePattern)
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	if _, err = io.Copy(dst, src); err == nil {
+		err = dst.Close()
+	} else {
+		dst.Close()
+	}
+	if err == nil {
+		err = checkRdbFile(dst.Name())
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// checkRdbFile 检查路径是否为RDB文件：必须是绝对路径、普通文件，并以REDIS开头
+func checkRdbFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("请上传RDB文件或指定文件路径")
+	}
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("文件路径必须是绝对路径")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s不是普通文件", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	defer file.Close()
+	magic := make([]byte, 5)
+	if _, err := io.ReadFull(file, magic); err != nil || string(magic) != "REDIS" {
+		return fmt.Errorf("%s不是RDB文件", path)
+	}
+	return nil
+}
+
+func removeRdbUpload(uploadId string) {
+	if uploadId == "" {
+		return
+	}
+	if path, ok := rdbUploads.LoadAndDelete(uploadId); ok {
+		os.Remove(path.(string))
+	}
+}
+
+// rdbAnalysisJob RDB离线分析任务，按文件偏移量计算进度
+func (this *RedisController) rdbAnalysisJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisRdbAnalysisParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	normalizeRdbAnalysisParams(params)
+
+	path := params.Path
+	if params.UploadId != "" {
+		uploaded, ok := rdbUploads.Load(params.UploadId)
+		if !ok {
+			return nil, fmt.Errorf("上传的文件已失效，插件重启后需要重新上传")
+		}
+		path = uploaded.(string)
+		defer removeRdbUpload(params.UploadId)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	j.SetTotal(info.Size())
+
+	report := newRdbReport(params)
+	parser := rdb.NewParser(file)
+	var processed int64
+
+	header, err := parser.Parse(func(entry *rdb.Entry) error {
+		if report.createdAt == 0 {
+			report.createdAt = rdbCreatedAt(parser.Header(), info)
+		}
+		report.add(entry)
+		if report.result.TotalKeys%rdbProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			j.AddProcessed(parser.Offset() - processed)
+			processed = parser.Offset()
+			j.SetMessage(fmt.Sprintf("已解析%d个Key", report.result.TotalKeys))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	j.AddProcessed(info.Size() - processed)
+
+	report.createdAt = rdbCreatedAt(header, info)
+	return report.finish(header, info.Size()), nil
+}
+
+// rdbCreatedAt RDB生成时间（unix秒），取自ctime辅助字段，没有时使用文件修改时间
+func rdbCreatedAt(header *rdb.Header, info os.FileInfo) int64 {
+	if ctime, err := strconv.ParseInt(header.Aux["ctime"], 10, 64); err == nil && ctime > 0 {
+		return ctime
+	}
+	return info.ModTime().Unix()
+}
+
+func normalizeRdbAnalysisParams(params *dto.RedisRdbAnalysisParams) {
+	if params.Delimiter == "" {
+		params.Delimiter = defaultRdbKeyDelimiter
+	}
+	if params.PrefixDepth <= 0 {
+		params.PrefixDepth = defaultRdbPrefixDepth
+	}
+	if params.PrefixDepth > maxRdbPrefixDepth {
+		params.PrefixDepth = maxRdbPrefixDepth
+	}
+	if params.TopN <= 0 {
+		params.TopN = defaultRdbTopN
+	}
+	if params.MaxPrefixes <= 0 {
+		params.MaxPrefixes = defaultRdbMaxPrefixes
+	}
+}
+
+// rdbReport RDB分析结果的累加器
+type rdbReport struct {
+	params        *dto.RedisRdbAnalysisParams
+	createdAt     int64 // RDB生成时间（unix秒），用于判断Key是否已过期
+	result        *vo.RedisRdbAnalysisResult
+	keys          []rdb.Entry // TopN候选，按大小
+	elementKeys   []rdb.Entry // TopN候选，按元素数量
+	databases     map[int]*vo.RedisRdbDatabaseStat
+	typeStats     map[string]*vo.RedisTypeMemoryStat
+	encodingStats map[string]*vo.RedisRdbEncodingStat
+	prefixes      map[string]*vo.RedisRdbPrefixStat
+}
+
+func newRdbReport(params *dto.RedisRdbAnalysisParams) *rdbReport {
+	return &rdbReport{
+		params:        params,
+		result:        &vo.RedisRdbAnalysisResult{Delimiter: params.Delimiter},
+		databases:     map[int]*vo.RedisRdbDatabaseStat{},
+		typeStats:     map[string]*vo.RedisTypeMemoryStat{},
+		encodingStats: map[string]*vo.RedisRdbEncodingStat{},
+		prefixes:      map[string]*vo.RedisRdbPrefixStat{},
+	}
+}
+
+func (this *rdbReport) add(entry *rdb.Entry) {
+	this.result.TotalKeys++
+	this.result.TotalSize += entry.Size
+
+	db, ok := this.databases[entry.Database]
+	if !ok {
+		db = &vo.RedisRdbDatabaseStat{Database: entry.Database}
+		this.databases[entry.Database] = db
+	}
+	db.Keys++
+	db.TotalSize += entry.Size
+	if entry.ExpireAt > 0 {
+		db.ExpiringKeys++
+		// 已过期但尚未被删除的Key仍会写入RDB
+		if entry.ExpireAt <= this.createdAt*1000 {
+			this.result.ExpiredKeys++
+		}
+	}
+
+	typeStat, ok := this.typeStats[entry.Type]
+	if !ok {
+		typeStat = &vo.RedisTypeMemoryStat{Type: entry.Type}
+		this.typeStats[entry.Type] = typeStat
+	}
+	typeStat.Count++
+	typeStat.TotalSize += entry.Size
+
+	encodingKey := entry.Type + "/" + entry.Encoding
+	encodingStat, ok := this.encodingStats[encodingKey]
+	if !ok {
+		encodingStat = &vo.RedisRdbEncodingStat{Type: entry.Type, Encoding: entry.Encoding}
+		this.encodingStats[encodingKey] = encodingStat
+	}
+	encodingStat.Count++
+	encodingStat.TotalSize += entry.Size
+
+	this.addPrefixes(entry)
+
+	// 定期截断，避免TopN候选集无限增长
+	this.keys = append(this.keys, *entry)
+	if len(this.keys) > this.params.TopN*2 {
+		this.keys = topRdbEntries(this.keys, this.params.TopN, rdbEntryBySize)
+	}
+	this.elementKeys = append(this.elementKeys, *entry)
+	if len(this.elementKeys) > this.params.TopN*2 {
+		this.elementKeys = topRdbEntries(this.elementKeys, this.params.TopN, rdbEntryByElements)
+	}
+}
+
+// addPrefixes 按分隔符将Key计入各层前缀，如a:b:c在深度2时计入a:与a:b:
+func (this *rdbReport) addPrefixes(entry *rdb.Entry) {
+	end := 0
+	for depth := 0; depth < this.params.PrefixDepth; depth++ {
+		index := strings.Index(entry.Key[end:], this.params.Delimiter)
+		if index < 0 {
+			return
+		}
+		end += index + len(this.params.Delimiter)
+		prefix := entry.Key[:end]
+
+		stat, ok := this.prefixes[prefix]
+		if !ok {
+			if len(this.prefixes) >= maxRdbTrackedPrefixes {
+				this.result.PrefixesTruncated = true
+				return
+			}
+			stat = &vo.RedisRdbPrefixStat{Prefix: prefix, TypeBreakdown: map[string]int64{}}
+			this.prefixes[prefix] = stat
+		}
+		stat.Keys++
+		stat.TotalSize += entry.Size
+		stat.Elements += entry.Elements
+		stat.TypeBreakdown[entry.Type]++
+	}
+}
+
+func (this *rdbReport) finish(header *rdb.Header, fileSize int64) *vo.RedisRdbAnalysisResult {
+	result := this.result
+	result.RdbVersion = header.Version
+	result.RedisVersion = header.Aux["redis-ver"]
+	result.CreatedAt = this.createdAt
+	result.FileSize = fileSize
+
+	result.TopKeys = []vo.RedisRdbKeyInfo{}
+	for _, entry := range topRdbEntries(this.keys, this.params.TopN, rdbEntryBySize) {
+		result.TopKeys = append(result.TopKeys, toRdbKeyInfo(entry, this.createdAt))
+	}
+	result.TopByElements = []vo.RedisRdbKeyInfo{}
+	for _, entry := range topRdbEntries(this.elementKeys, this.params.TopN, rdbEntryByElements) {
+		result.TopByElements = append(result.TopByElements, toRdbKeyInfo(entry, this.createdAt))
+	}
+
+	for _, stat := range this.databases {
+		result.Databases = append(result.Databases, *stat)
+	}
+	sort.Slice(result.Databases, func(i, k int) bool {
+		return result.Databases[i].Database < result.Databases[k].Database
+	})
+
+	for _, stat := range this.typeStats {
+		result.TypeStats = append(result.TypeStats, *stat)
+	}
+	sort.Slice(result.TypeStats, func(i, k int) bool {
+		return result.TypeStats[i].TotalSize > result.TypeStats[k].TotalSize
+	})
+
+	for _, stat := range this.encodingStats {
+		result.EncodingStats = append(result.EncodingStats, *stat)
+	}
+	sort.Slice(result.EncodingStats, func(i, k int) bool {
+		return result.EncodingStats[i].TotalSize > result.EncodingStats[k].TotalSize
+	})
+
+	for _, stat := range this.prefixes {
+		result.Prefixes = append(result.Prefixes, *stat)
+	}
+	sort.Slice(result.Prefixes, func(i, k int) bool {
+		if result.Prefixes[i].TotalSize != result.Prefixes[k].TotalSize {
+			return result.Prefixes[i].TotalSize > result.Prefixes[k].TotalSize
+		}
+		return result.Prefixes[i].Prefix < result.Prefixes[k].Prefix
+	})
+	if len(result.Prefixes) > this.params.MaxPrefixes {
+		result.Prefixes = result.Prefixes[:this.params.MaxPrefixes]
+	}
+
+	return result
+}
+
+func rdbEntryBySize(a, b rdb.Entry) bool {
+	return a.Size > b.Size
+}
+
+func rdbEntryByElements(a, b rdb.Entry) bool {
+	return a.Elements > b.Elements
+}
+
+// topRdbEntries 按less排序后保留前n个
+func topRdbEntries(entries []rdb.Entry, n int, less func(a, b rdb.Entry) bool) []rdb.Entry {
+	sort.Slice(entries, func(i, k int) bool {
+		return less(entries[i], entries[k])
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// toRdbKeyInfo 转换为Key信息，TTL按RDB生成时间计算，向上取整
+func toRdbKeyInfo(entry rdb.Entry, createdAt int64) vo.RedisRdbKeyInfo {
+	info := vo.RedisRdbKeyInfo{
+		Key:       entry.Key,
+		Database:  entry.Database,
+		Type:      entry.Type,
+		Encoding:  entry.Encoding,
+		SizeBytes: entry.Size,
+		Elements:  entry.Elements,
+		TTL:       -1,
+		ExpireAt:  entry.ExpireAt,
+	}
+	if entry.ExpireAt > 0 {
+		remaining := time.Duration(entry.ExpireAt-createdAt*1000) * time.Millisecond
+		info.TTL = 0
+		if remaining > 0 {
+			info.TTL = int64((remaining + time.Second - 1) / time.Second)
+		}
+	}
+	return info
+}
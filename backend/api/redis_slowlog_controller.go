@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+const (
+	defaultSlowlogCount     = 128   // 默认读取的慢日志条数，与slowlog-max-len的默认值一致
+	maxSlowlogCount         = 10000 // 单次读取的慢日志条数上限
+	maxSlowlogGroupClients  = 20    // 每个分组保留的客户端地址数量上限
+	defaultSlowlogDelimiter = ":"
+)
+
+// slowlogIdSegmentRegexp 匹配key中像ID的片段：纯数字、UUID、长十六进制串
+var slowlogIdSegmentRegexp = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// GetSlowlogAction 获取慢日志 - SLOWLOG GET，并按命令与归一化后的key模式聚合耗时
+func (this *RedisController) GetSlowlogAction(ctx *gin.Context) {
+	req := new(dto.RedisSlowlogRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Count <= 0 {
+		req.Count = defaultSlowlogCount
+	}
+	if req.Count > maxSlowlogCount {
+		req.Count = maxSlowlogCount
+	}
+	if req.Delimiter == "" {
+		req.Delimiter = defaultSlowlogDelimiter
+	}
+
+	logger.DefaultLogger.Debug("查询Redis慢日志", "conn_id:", req.EsConnect, "database:", req.Database, "count:", req.Count)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := this.executeRedisCommandWithRetry(ctx, api, req.Database, "SLOWLOG", "GET", req.Count)
+	if err != nil {
+		logger.DefaultLogger.Error("SLOWLOG GET执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	entries := parseSlowlogEntries(result, req.Delimiter)
+	resp := vo.RedisSlowlogResponse{
+		Entries: entries,
+		Groups:  aggregateSlowlog(entries),
+	}
+
+	if length, err := this.slowlogLen(ctx, api, req.Database); err == nil {
+		resp.Len = length
+	} else {
+		logger.DefaultLogger.Warn("SLOWLOG LEN执行失败", "error:", err)
+	}
+	// 配置项读取失败（如CONFIG被禁用）不影响慢日志展示
+	if value, err := this.configGet(ctx, api, req.Database, "slowlog-log-slower-than"); err == nil {
+		resp.SlowerThanMicros = cast.ToInt64(value)
+	}
+	if value, err := this.configGet(ctx, api, req.Database, "slowlog-max-len"); err == nil {
+		resp.MaxLen = cast.ToInt64(value)
+	}
+
+	this.Success(ctx, response.SearchSuccess, resp)
+}
+
+// GetSlowlogLenAction 获取慢日志条数 - SLOWLOG LEN
+func (this *RedisController) GetSlowlogLenAction(ctx *gin.Context) {
+	req := new(dto.RedisInfoRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	length, err := this.slowlogLen(ctx, api, req.Database)
+	if err != nil {
+		logger.DefaultLogger.Error("SLOWLOG LEN执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.SearchSuccess, vo.RedisSlowlogLenResponse{Len: length})
+}
+
+// ResetSlowlogAction 清空慢日志 - SLOWLOG RESET
+func (this *RedisController) ResetSlowlogAction(ctx *gin.Context) {
+	req := new(dto.RedisInfoRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Info("清空Redis慢日志", "conn_id:", req.EsConnect)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	if _, err = api.RedisExecCommand(ctx, req.Database, "SLOWLOG", "RESET"); err != nil {
+		logger.DefaultLogger.Error("SLOWLOG RESET执行失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: true,
+		Message: "慢日志已清空",
+	})
+}
+
+func (this *RedisController) slowlogLen(ctx context.Context, api *ev_api.EvApiAdapter, database int) (int64, error) {
+	result, err := this.executeRedisCommandWithRetry(ctx, api, database, "SLOWLOG", "LEN")
+	if err != nil {
+		return 0, err
+	}
+	return cast.ToInt64(result), nil
+}
+
+// parseSlowlogEntries 解析SLOWLOG GET的返回：[id, 时间戳, 耗时(微秒), [参数...], 客户端地址, 客户端名称]，后两项4.0起才有
+func parseSlowlogEntries(result interface{}, delimiter string) []vo.RedisSlowlogEntry {
+	items := cast.ToSlice(result)
+	entries := make([]vo.RedisSlowlogEntry, 0, len(items))
+	for _, item := range items {
+		fields := cast.ToSlice(item)
+		if len(fields) < 4 {
+			continue
+		}
+
+		entry := vo.RedisSlowlogEntry{
+			Id:             cast.ToInt64(fields[0]),
+			Timestamp:      cast.ToInt64(fields[1]),
+			DurationMicros: cast.ToInt64(fields[2]),
+		}
+		for _, arg := range cast.ToSlice(fields[3]) {
+			entry.Args = append(entry.Args, cast.ToString(arg))
+		}
+		if len(fields) > 4 {
+			entry.ClientAddr = cast.ToString(fields[4])
+		}
+		if len(fields) > 5 {
+			entry.ClientName = cast.ToString(fields[5])
+		}
+
+		if len(entry.Args) > 0 {
+			entry.Command = strings.ToUpper(entry.Args[0])
+			keys := monitorCommandKeys(strings.ToLower(entry.Args[0]), entry.Args[1:])
+			if len(keys) > 0 {
+				entry.Key = keys[0]
+				entry.KeyPattern = normalizeKeyPattern(keys[0], delimiter)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// normalizeKeyPattern 将key中像ID的片段替换为*，如user:1001:profile归一化为user:*:profile
+func normalizeKeyPattern(key, delimiter string) string {
+	segments := strings.Split(key, delimiter)
+	for i, segment := range segments {
+		if slowlogIdSegmentRegexp.MatchString(segment) {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, delimiter)
+}
+
+// aggregateSlowlog 按命令与key模式分组，统计次数与耗时分位数，按总耗时倒序
+func aggregateSlowlog(entries []vo.RedisSlowlogEntry) []vo.RedisSlowlogGroup {
+	type groupKey struct {
+		command    string
+		keyPattern string
+	}
+	groups := map[groupKey]*vo.RedisSlowlogGroup{}
+	durations := map[groupKey][]int64{}
+	clients := map[groupKey]map[string]bool{}
+
+	for _, entry := range entries {
+		key := groupKey{command: entry.Command, keyPattern: entry.KeyPattern}
+		group, ok := groups[key]
+		if !ok {
+			group = &vo.RedisSlowlogGroup{
+				Command:        entry.Command,
+				KeyPattern:     entry.KeyPattern,
+				FirstTimestamp: entry.Timestamp,
+				Clients:        []string{},
+			}
+			groups[key] = group
+			clients[key] = map[string]bool{}
+		}
+		group.Count++
+		group.TotalMicros += entry.DurationMicros
+		if entry.DurationMicros > group.MaxMicros {
+			group.MaxMicros = entry.DurationMicros
+		}
+		if entry.Timestamp < group.FirstTimestamp {
+			group.FirstTimestamp = entry.Timestamp
+		}
+		if entry.Timestamp > group.LastTimestamp {
+			group.LastTimestamp = entry.Timestamp
+		}
+		durations[key] = append(durations[key], entry.DurationMicros)
+
+		if entry.ClientAddr != "" && !clients[key][entry.ClientAddr] {
+			if len(clients[key]) < maxSlowlogGroupClients {
+				group.Clients = append(group.Clients, entry.ClientAddr)
+			} else {
+				group.ClientsTruncated = true
+			}
+			clients[key][entry.ClientAddr] = true
+		}
+	}
+
+	result := make([]vo.RedisSlowlogGroup, 0, len(groups))
+	for key, group := range groups {
+		values := durations[key]
+		sort.Slice(values, func(i, k int) bool { return values[i] < values[k] })
+		group.P50Micros = percentile(values, 50)
+		group.P99Micros = percentile(values, 99)
+		group.ClientCount = len(clients[key])
+		sort.Strings(group.Clients)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, k int) bool {
+		if result[i].TotalMicros != result[k].TotalMicros {
+			return result[i].TotalMicros > result[k].TotalMicros
+		}
+		return result[i].Command+result[i].KeyPattern < result[k].Command+result[k].KeyPattern
+	})
+	return result
+}
+
+// percentile 最近秩法计算已排序数据的分位数
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // 向上取整
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
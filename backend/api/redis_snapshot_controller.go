@@ -0,0 +1,497 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/snapshot"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxSnapshotKeys        = 100000            // 单个快照最多包含的Key数量
+	maxSnapshotKeyBytes    = 4 * 1024 * 1024   // 单个Key的内存上限，DUMP前按MEMORY USAGE检查，与迁移的上限一致
+	maxSnapshotBytes       = 256 * 1024 * 1024 // 单个快照的DUMP序列化值总量上限
+	maxSnapshotsPerConnect = 50                // 每个数据源保留的快照数量上限
+	snapshotInsertBatch    = 50                // 每条INSERT写入的明细数量
+	snapshotRestoreBatch   = 100               // 恢复时每批读取的明细数量
+	maxSnapshotFailures    = 1000              // 恢复结果中保留的失败明细数量上限
+)
+
+// CreateSnapshotAction 创建Key快照 - 以后台任务的方式DUMP匹配的Key，连同剩余TTL保存到插件自身的数据库
+// 快照超过数量或大小上限时整体失败，不保留不完整的快照
+func (this *RedisController) CreateSnapshotAction(ctx *gin.Context) {
+	req := new(dto.RedisSnapshotCreateRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if len(req.Keys) > maxSnapshotKeys {
+		this.Error(ctx, fmt.Errorf("一次最多指定%d个Key", maxSnapshotKeys))
+		return
+	}
+	pattern := ""
+	if len(req.Keys) == 0 {
+		if pattern, _, err = buildPatternMatcher(&req.RedisKeyPatternOptions); err != nil {
+			this.Error(ctx, err)
+			return
+		}
+	}
+
+	snapshots, err := this.snapshotStore.List(ctx, req.EsConnect, maxSnapshotsPerConnect)
+	if err != nil {
+		logger.DefaultLogger.Error("获取快照列表失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+	if len(snapshots) >= maxSnapshotsPerConnect {
+		this.Error(ctx, fmt.Errorf("每个数据源最多保留%d个快照，请先删除不需要的快照", maxSnapshotsPerConnect))
+		return
+	}
+
+	now := time.Now()
+	if req.Name == "" {
+		req.Name = "快照" + now.Format("2006-01-02 15:04:05")
+	}
+	snap := &snapshot.Snapshot{
+		ID:        randomHex(16),
+		Name:      req.Name,
+		EsConnect: req.EsConnect,
+		DbIndex:   req.Database,
+		UserId:    util.GetEvUserID(ctx),
+		Pattern:   pattern,
+		Status:    snapshot.StatusCreating,
+		CreatedAt: now.Unix(),
+	}
+	req.SnapshotId = snap.ID
+
+	params, err := json.Marshal(req.RedisSnapshotParams)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("创建Redis Key快照", "conn_id:", req.EsConnect, "database:", req.Database,
+		"snapshot_id:", snap.ID, "keys:", len(req.Keys), "pattern:", pattern)
+
+	if err = this.snapshotStore.Insert(ctx, snap); err != nil {
+		logger.DefaultLogger.Error("保存快照记录失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	j, err := this.jobManager.Start(ctx, JobKindSnapshot, req.EsConnect, req.Database, snap.UserId, params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动快照任务失败", "error:", err)
+		_ = this.snapshotStore.Delete(ctx, snap.ID)
+		this.Error(ctx, err)
+		return
+	}
+
+	snap.JobId = j.ID // 由任务自身写入数据库，避免覆盖任务已更新的状态
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisSnapshotCreateResponse{
+		Snapshot: toSnapshotInfo(snap),
+		Job:      toJobInfo(j.Record()),
+	})
+}
+
+// RestoreSnapshotAction 从快照恢复Key - 以后台任务的方式RESTORE REPLACE到快照所在的连接与库
+// TTL恢复为快照时的剩余TTL；快照之后新建的Key不会被删除
+func (this *RedisController) RestoreSnapshotAction(ctx *gin.Context) {
+	req := new(dto.RedisSnapshotIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	snap, err := this.snapshotStore.Get(ctx, req.SnapshotId)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if snap.Status != snapshot.StatusReady {
+		this.Error(ctx, fmt.Errorf("快照状态为%s，只能从已完成的快照恢复", snap.Status))
+		return
+	}
+
+	params, err := json.Marshal(dto.RedisSnapshotRestoreParams{SnapshotId: snap.ID})
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	logger.DefaultLogger.Debug("从快照恢复Redis Key", "conn_id:", snap.EsConnect, "database:", snap.DbIndex,
+		"snapshot_id:", snap.ID, "keys:", snap.KeyCount)
+
+	j, err := this.jobManager.Start(ctx, JobKindSnapshotRestore, snap.EsConnect, snap.DbIndex, util.GetEvUserID(ctx), params)
+	if err != nil {
+		logger.DefaultLogger.Error("启动快照恢复任务失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toJobInfo(j.Record()))
+}
+
+// DeleteSnapshotAction 删除快照及其明细
+func (this *RedisController) DeleteSnapshotAction(ctx *gin.Context) {
+	req := new(dto.RedisSnapshotIdRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	snap, err := this.snapshotStore.Get(ctx, req.SnapshotId)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if snap.Status == snapshot.StatusCreating {
+		// 插件重启会中断创建任务，此时快照不会再完成，允许删除
+		record, err := this.jobManager.Get(ctx, snap.JobId)
+		if err == nil && (record.Status == job.StatusPending || record.Status == job.StatusRunning) {
+			this.Error(ctx, fmt.Errorf("快照正在创建中，请先取消任务%s", snap.JobId))
+			return
+		}
+	}
+
+	if err = this.snapshotStore.Delete(ctx, snap.ID); err != nil {
+		logger.DefaultLogger.Error("删除快照失败", "snapshot_id:", snap.ID, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, toSnapshotInfo(snap))
+}
+
+// ListSnapshotsAction 获取快照列表
+func (this *RedisController) ListSnapshotsAction(ctx *gin.Context) {
+	req := new(dto.RedisSnapshotListRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 100
+	}
+
+	snapshots, err := this.snapshotStore.List(ctx, req.EsConnect, req.Limit)
+	if err != nil {
+		logger.DefaultLogger.Error("获取快照列表失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	infos := make([]vo.RedisSnapshotInfo, 0, len(snapshots))
+	for _, snap := range snapshots {
+		infos = append(infos, toSnapshotInfo(snap))
+	}
+
+	this.Success(ctx, response.SearchSuccess, vo.RedisSnapshotListResponse{
+		Snapshots: infos,
+	})
+}
+
+// SnapshotDetailAction 获取快照信息并分页列出其中的Key（不返回序列化值）
+func (this *RedisController) SnapshotDetailAction(ctx *gin.Context) {
+	req := new(dto.RedisSnapshotDetailRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultValuePageSize
+	}
+	if req.Limit > maxValuePageSize {
+		req.Limit = maxValuePageSize
+	}
+
+	snap, err := this.snapshotStore.Get(ctx, req.SnapshotId)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	keys, err := this.snapshotStore.Keys(ctx, snap.ID, req.AfterId, req.Limit, false)
+	if err != nil {
+		logger.DefaultLogger.Error("获取快照明细失败", "snapshot_id:", snap.ID, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	resp := vo.RedisSnapshotDetailResponse{
+		Snapshot: toSnapshotInfo(snap),
+		Keys:     make([]vo.RedisSnapshotKeyInfo, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, vo.RedisSnapshotKeyInfo{Id: key.ID, Key: key.KeyName, Pttl: key.Pttl, Size: key.Size})
+	}
+	if len(keys) == req.Limit {
+		resp.NextAfterId = keys[len(keys)-1].ID
+	}
+
+	this.Success(ctx, response.SearchSuccess, resp)
+}
+
+// snapshotJob 创建快照任务，失败或取消时清理已写入的明细并将快照标记为失败
+func (this *RedisController) snapshotJob(ctx context.Context, j *job.Job) (result interface{}, err error) {
+	params := new(dto.RedisSnapshotParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	snap, err := this.snapshotStore.Get(ctx, params.SnapshotId)
+	if err != nil {
+		return nil, err
+	}
+	maxKeys := params.MaxKeys
+	if maxKeys <= 0 || maxKeys > maxSnapshotKeys {
+		maxKeys = maxSnapshotKeys
+	}
+
+	snap.JobId = j.ID
+	if err := this.snapshotStore.Update(ctx, snap); err != nil {
+		logger.DefaultLogger.Warn("保存快照任务ID失败", "snapshot_id:", snap.ID, "error:", err)
+	}
+
+	defer func() {
+		// 任务可能已被取消，使用独立的context收尾
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		snap.FinishedAt = time.Now().Unix()
+		if err != nil {
+			snap.Status = snapshot.StatusFailed
+			snap.Message = err.Error()
+			if delErr := this.snapshotStore.DeleteKeys(cleanupCtx, snap.ID); delErr != nil {
+				logger.DefaultLogger.Error("清理失败快照的明细失败", "snapshot_id:", snap.ID, "error:", delErr)
+			}
+		} else {
+			snap.Status = snapshot.StatusReady
+			snap.Message = ""
+		}
+		if updateErr := this.snapshotStore.Update(cleanupCtx, snap); updateErr != nil {
+			logger.DefaultLogger.Error("更新快照状态失败", "snapshot_id:", snap.ID, "error:", updateErr)
+		}
+	}()
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	handle := func(batch []string) error {
+		keys, err := this.dumpSnapshotKeys(ctx, api, j.Database, snap.ID, batch)
+		if err != nil {
+			return err
+		}
+
+		var batchBytes int64
+		for _, key := range keys {
+			batchBytes += key.Size
+		}
+		if snap.KeyCount+int64(len(keys)) > int64(maxKeys) {
+			return fmt.Errorf("匹配的Key超过%d个，请缩小匹配范围", maxKeys)
+		}
+		if snap.TotalBytes+batchBytes > maxSnapshotBytes {
+			return fmt.Errorf("快照大小超过%dMB的上限，请缩小匹配范围", maxSnapshotBytes/1024/1024)
+		}
+
+		for start := 0; start < len(keys); start += snapshotInsertBatch {
+			end := start + snapshotInsertBatch
+			if end > len(keys) {
+				end = len(keys)
+			}
+			if err := this.snapshotStore.InsertKeys(ctx, keys[start:end]); err != nil {
+				return fmt.Errorf("保存快照明细失败: %w", err)
+			}
+		}
+
+		snap.KeyCount += int64(len(keys))
+		snap.TotalBytes += batchBytes
+		j.AddProcessed(int64(len(batch)))
+		j.SetMessage(fmt.Sprintf("已保存%d个Key，共%d字节", snap.KeyCount, snap.TotalBytes))
+		return nil
+	}
+
+	if len(params.Keys) > 0 {
+		j.SetTotal(int64(len(params.Keys)))
+		for start := 0; start < len(params.Keys); start += snapshotRestoreBatch {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			end := start + snapshotRestoreBatch
+			if end > len(params.Keys) {
+				end = len(params.Keys)
+			}
+			if err := handle(params.Keys[start:end]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		pattern, filter, err := buildPatternMatcher(&params.RedisKeyPatternOptions)
+		if err != nil {
+			return nil, err
+		}
+		batchSize, keysPerSecond := normalizeBulkRate(0, 0)
+		if _, _, err := this.scanMatchedBatches(ctx, j, api, pattern, filter, batchSize, keysPerSecond, handle); err != nil {
+			return nil, err
+		}
+	}
+
+	info := toSnapshotInfo(snap)
+	info.Status = snapshot.StatusReady
+	return info, nil
+}
+
+// dumpSnapshotKeys 并发DUMP一批Key，已不存在的Key跳过，任一Key失败时返回错误
+func (this *RedisController) dumpSnapshotKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, snapshotId string, keys []string) ([]*snapshot.Key, error) {
+	rows := make([]*snapshot.Key, 0, len(keys))
+	var mu sync.Mutex // 保护rows
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			pttl, payload, err := this.dumpKeyHex(gctx, api, database, key, maxSnapshotKeyBytes)
+			if err != nil {
+				return fmt.Errorf("DUMP %s 失败: %w", key, err)
+			}
+			if payload == "" {
+				return nil // key已被删除
+			}
+
+			mu.Lock()
+			rows = append(rows, &snapshot.Key{
+				SnapshotId: snapshotId,
+				KeyName:    key,
+				Pttl:       pttl,
+				Size:       int64(len(payload) / 2),
+				Payload:    payload,
+			})
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// snapshotRestoreJob 从快照恢复Key的任务，按明细ID记录断点，支持继续执行
+func (this *RedisController) snapshotRestoreJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisSnapshotRestoreParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	snap, err := this.snapshotStore.Get(ctx, params.SnapshotId)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Status != snapshot.StatusReady {
+		return nil, fmt.Errorf("快照状态为%s，只能从已完成的快照恢复", snap.Status)
+	}
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	j.SetTotal(snap.KeyCount)
+	// 续跑时在原任务中断前的结果上继续累计，之前记录的失败明细不会丢失
+	result := &vo.RedisSnapshotRestoreResult{}
+	if err := j.BindResult(result); err != nil {
+		return nil, err
+	}
+	result.SnapshotId = snap.ID
+	if result.Failures == nil {
+		result.Failures = []vo.RedisMigrateFailure{}
+	}
+	j.TrackResult(result)
+	var mu sync.Mutex // 保护result
+
+	afterId := cast.ToInt64(j.Checkpoint())
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		keys, err := this.snapshotStore.Keys(ctx, snap.ID, afterId, snapshotRestoreBatch, true)
+		if err != nil {
+			return nil, fmt.Errorf("读取快照明细失败: %w", err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(memoryAnalysisConcurrency)
+		for _, key := range keys {
+			key := key // 避免闭包问题
+			g.Go(func() error {
+				_, err := this.restoreKeyHex(gctx, api, j.Database, key.KeyName, key.Pttl, key.Payload, true)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					logger.DefaultLogger.Debug("从快照恢复Key失败", "key:", key.KeyName, "error:", err)
+					result.Failed++
+					j.AddFailed(1)
+					if len(result.Failures) < maxSnapshotFailures {
+						result.Failures = append(result.Failures, vo.RedisMigrateFailure{Key: key.KeyName, Error: err.Error()})
+					} else {
+						result.FailuresTruncated = true
+					}
+					return nil
+				}
+				result.Restored++
+				return nil
+			})
+		}
+		_ = g.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		afterId = keys[len(keys)-1].ID
+		j.AddProcessed(int64(len(keys)))
+		j.SetCheckpoint(strconv.FormatInt(afterId, 10))
+		j.SetMessage(fmt.Sprintf("已恢复%d个Key，失败%d个", result.Restored, result.Failed))
+	}
+
+	return result, nil
+}
+
+func toSnapshotInfo(snap *snapshot.Snapshot) vo.RedisSnapshotInfo {
+	return vo.RedisSnapshotInfo{
+		SnapshotId: snap.ID,
+		Name:       snap.Name,
+		EsConnect:  snap.EsConnect,
+		Database:   snap.DbIndex,
+		Pattern:    snap.Pattern,
+		JobId:      snap.JobId,
+		Status:     snap.Status,
+		KeyCount:   snap.KeyCount,
+		TotalBytes: snap.TotalBytes,
+		Message:    snap.Message,
+		CreatedAt:  snap.CreatedAt,
+		FinishedAt: snap.FinishedAt,
+	}
+}
@@ -0,0 +1,424 @@
+package api
+
+import (
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+)
+
+// getStreamDetail 读取Stream详情：XRANGE/XREVRANGE分页读取消息，XINFO获取概要、消费组与消费者
+func (this *RedisController) getStreamDetail(ctx context.Context, api *ev_api.EvApiAdapter, req *dto.RedisKeyDetailRequest) (vo.RedisStreamDetail, error) {
+	detail := vo.RedisStreamDetail{
+		Entries: []vo.RedisStreamEntry{},
+		Groups:  []vo.RedisStreamGroup{},
+	}
+
+	pageSize := normalizeValuePageSize(req.PageSize)
+
+	// 多取一条用于确定下一页的起始ID
+	var result interface{}
+	var err error
+	if req.Reverse {
+		end := req.Cursor
+		if end == "" {
+			end = "+"
+		}
+		result, err = api.RedisExecCommand(ctx, req.Database, "XREVRANGE", req.Key, end, "-", "COUNT", pageSize+1)
+	} else {
+		start := req.Cursor
+		if start == "" {
+			start = "-"
+		}
+		result, err = api.RedisExecCommand(ctx, req.Database, "XRANGE", req.Key, start, "+", "COUNT", pageSize+1)
+	}
+	if err != nil {
+		return detail, fmt.Errorf("读取Stream消息失败: %w", err)
+	}
+
+	entries := parseStreamEntries(result)
+	if len(entries) > pageSize {
+		detail.NextCursor = entries[pageSize].Id
+		entries = entries[:pageSize]
+	}
+	detail.Entries = entries
+
+	infoResult, err := api.RedisExecCommand(ctx, req.Database, "XINFO", "STREAM", req.Key)
+	if err != nil {
+		return detail, fmt.Errorf("XINFO STREAM执行失败: %w", err)
+	}
+	info := replyToMap(infoResult)
+	detail.Length = cast.ToInt64(info["length"])
+	detail.LastGeneratedId = cast.ToString(info["last-generated-id"])
+	detail.FirstEntry = parseStreamEntry(info["first-entry"])
+	detail.LastEntry = parseStreamEntry(info["last-entry"])
+
+	groupsResult, err := api.RedisExecCommand(ctx, req.Database, "XINFO", "GROUPS", req.Key)
+	if err != nil {
+		return detail, fmt.Errorf("XINFO GROUPS执行失败: %w", err)
+	}
+	for _, item := range cast.ToSlice(groupsResult) {
+		groupInfo := replyToMap(item)
+		group := vo.RedisStreamGroup{
+			Name:            cast.ToString(groupInfo["name"]),
+			Consumers:       cast.ToInt64(groupInfo["consumers"]),
+			Pending:         cast.ToInt64(groupInfo["pending"]),
+			LastDeliveredId: cast.ToString(groupInfo["last-delivered-id"]),
+			Lag:             -1,
+			ConsumerList:    []vo.RedisStreamConsumer{},
+		}
+		// lag在Redis 7.0之前不存在，无法计算时为nil
+		if lag, ok := groupInfo["lag"]; ok && lag != nil {
+			group.Lag = cast.ToInt64(lag)
+		}
+
+		consumersResult, err := api.RedisExecCommand(ctx, req.Database, "XINFO", "CONSUMERS", req.Key, group.Name)
+		if err != nil {
+			logger.DefaultLogger.Warn("XINFO CONSUMERS执行失败", "key:", req.Key, "group:", group.Name, "error:", err)
+		}
+		for _, consumerItem := range cast.ToSlice(consumersResult) {
+			consumerInfo := replyToMap(consumerItem)
+			consumer := vo.RedisStreamConsumer{
+				Name:     cast.ToString(consumerInfo["name"]),
+				Pending:  cast.ToInt64(consumerInfo["pending"]),
+				Idle:     cast.ToInt64(consumerInfo["idle"]),
+				Inactive: -1,
+			}
+			if inactive, ok := consumerInfo["inactive"]; ok {
+				consumer.Inactive = cast.ToInt64(inactive)
+			}
+			group.ConsumerList = append(group.ConsumerList, consumer)
+		}
+
+		detail.Groups = append(detail.Groups, group)
+	}
+
+	return detail, nil
+}
+
+// streamReplaceArgs 构建Stream类型的写入参数，每条消息编码为：id, 字段数, field1, value1, ...
+// value为消息列表，每条消息包含id（可省略，默认*）与fields
+func streamReplaceArgs(req *dto.RedisSetKeyRequest) ([]interface{}, error) {
+	streamData, ok := req.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid stream data format")
+	}
+
+	var args []interface{}
+	for _, item := range streamData {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid stream entry format")
+		}
+		id := cast.ToString(entry["id"])
+		if id == "" {
+			id = "*"
+		}
+		fields, err := parseStreamFieldsValue(entry["fields"])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, id, strconv.Itoa(len(fields)))
+		for _, field := range fields {
+			args = append(args, field.Field, field.Value)
+		}
+	}
+	return args, nil
+}
+
+// StreamAddAction 向Stream追加消息 (XADD)
+func (this *RedisController) StreamAddAction(ctx *gin.Context) {
+	req := new(dto.RedisStreamAddRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Key == "" {
+		this.Error(ctx, fmt.Errorf("key不能为空"))
+		return
+	}
+	fields, err := parseStreamFieldsValue(req.Fields)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+	if req.Id == "" {
+		req.Id = "*"
+	}
+
+	logger.DefaultLogger.Debug("Stream追加消息", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "id:", req.Id)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"XADD", req.Key}
+	if req.MaxLen > 0 {
+		args = append(args, "MAXLEN")
+		if req.Approximate {
+			args = append(args, "~")
+		}
+		args = append(args, req.MaxLen)
+	}
+	args = append(args, req.Id)
+	for _, field := range fields {
+		args = append(args, field.Field, field.Value)
+	}
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("XADD执行失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisStreamAddResponse{
+		Id: cast.ToString(result),
+	})
+}
+
+// StreamTrimAction 裁剪Stream (XTRIM MAXLEN/MINID)
+func (this *RedisController) StreamTrimAction(ctx *gin.Context) {
+	req := new(dto.RedisStreamTrimRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	strategy := strings.ToUpper(req.Strategy)
+	if strategy != "MAXLEN" && strategy != "MINID" {
+		this.Error(ctx, fmt.Errorf("不支持的裁剪策略: %s", req.Strategy))
+		return
+	}
+	if req.Threshold == "" {
+		this.Error(ctx, fmt.Errorf("裁剪阈值不能为空"))
+		return
+	}
+	if strategy == "MAXLEN" {
+		if maxLen, err := cast.ToInt64E(req.Threshold); err != nil || maxLen < 0 {
+			this.Error(ctx, fmt.Errorf("MAXLEN阈值必须为非负整数: %s", req.Threshold))
+			return
+		}
+	}
+
+	logger.DefaultLogger.Debug("Stream裁剪", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"strategy:", strategy, "threshold:", req.Threshold)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"XTRIM", req.Key, strategy}
+	if req.Approximate {
+		args = append(args, "~")
+	}
+	args = append(args, req.Threshold)
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("XTRIM执行失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisStreamAffectedResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// StreamDeleteAction 删除Stream中的消息 (XDEL)
+func (this *RedisController) StreamDeleteAction(ctx *gin.Context) {
+	req := new(dto.RedisStreamDeleteRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if len(req.Ids) == 0 {
+		this.Error(ctx, fmt.Errorf("消息ID不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("Stream删除消息", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "ids:", len(req.Ids))
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"XDEL", req.Key}
+	for _, id := range req.Ids {
+		args = append(args, id)
+	}
+
+	result, err := api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("XDEL执行失败", "key:", req.Key, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisStreamAffectedResponse{
+		Affected: cast.ToInt64(result),
+	})
+}
+
+// StreamGroupCreateAction 创建消费组 (XGROUP CREATE)
+func (this *RedisController) StreamGroupCreateAction(ctx *gin.Context) {
+	req := new(dto.RedisStreamGroupRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Group == "" {
+		this.Error(ctx, fmt.Errorf("消费组名称不能为空"))
+		return
+	}
+	if req.Id == "" {
+		req.Id = "$"
+	}
+
+	logger.DefaultLogger.Debug("创建Stream消费组", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key,
+		"group:", req.Group, "id:", req.Id)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	args := []interface{}{"XGROUP", "CREATE", req.Key, req.Group, req.Id}
+	if req.MkStream {
+		args = append(args, "MKSTREAM")
+	}
+
+	_, err = api.RedisExecCommand(ctx, req.Database, args...)
+	if err != nil {
+		logger.DefaultLogger.Error("XGROUP CREATE执行失败", "key:", req.Key, "group:", req.Group, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: true,
+		Message: "创建成功",
+	})
+}
+
+// StreamGroupDestroyAction 删除消费组 (XGROUP DESTROY)
+func (this *RedisController) StreamGroupDestroyAction(ctx *gin.Context) {
+	req := new(dto.RedisStreamGroupRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	if req.Group == "" {
+		this.Error(ctx, fmt.Errorf("消费组名称不能为空"))
+		return
+	}
+
+	logger.DefaultLogger.Debug("删除Stream消费组", "conn_id:", req.EsConnect, "database:", req.Database, "key:", req.Key, "group:", req.Group)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	result, err := api.RedisExecCommand(ctx, req.Database, "XGROUP", "DESTROY", req.Key, req.Group)
+	if err != nil {
+		logger.DefaultLogger.Error("XGROUP DESTROY执行失败", "key:", req.Key, "group:", req.Group, "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	success := cast.ToInt64(result) > 0
+	message := "消费组不存在"
+	if success {
+		message = "删除成功"
+	}
+
+	this.Success(ctx, response.OperateSuccess, vo.RedisOperationResponse{
+		Success: success,
+		Message: message,
+	})
+}
+
+// parseStreamEntries 解析XRANGE/XREVRANGE返回的消息列表：[[id, [field, value, ...]], ...]
+func parseStreamEntries(result interface{}) []vo.RedisStreamEntry {
+	items := cast.ToSlice(result)
+	entries := make([]vo.RedisStreamEntry, 0, len(items))
+	for _, item := range items {
+		if entry := parseStreamEntry(item); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// parseStreamEntry 解析单条消息，格式不正确时返回nil
+func parseStreamEntry(item interface{}) *vo.RedisStreamEntry {
+	parts := cast.ToSlice(item)
+	if len(parts) < 2 {
+		return nil
+	}
+	entry := &vo.RedisStreamEntry{
+		Id:     cast.ToString(parts[0]),
+		Fields: []vo.RedisStreamField{},
+	}
+	values := cast.ToSlice(parts[1])
+	for i := 0; i+1 < len(values); i += 2 {
+		entry.Fields = append(entry.Fields, vo.RedisStreamField{
+			Field: cast.ToString(values[i]),
+			Value: cast.ToString(values[i+1]),
+		})
+	}
+	return entry
+}
+
+// parseStreamFieldsValue 解析前端提交的消息字段，支持{field: value}对象或[{field, value}]数组（保持顺序）
+func parseStreamFieldsValue(value interface{}) ([]vo.RedisStreamField, error) {
+	var fields []vo.RedisStreamField
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			fields = append(fields, vo.RedisStreamField{Field: field, Value: cast.ToString(fieldValue)})
+		}
+	case []interface{}:
+		for _, item := range v {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid stream field format")
+			}
+			fields = append(fields, vo.RedisStreamField{
+				Field: cast.ToString(itemMap["field"]),
+				Value: cast.ToString(itemMap["value"]),
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("stream消息字段不能为空")
+	}
+	return fields, nil
+}
+
+// replyToMap 将键值对形式的回复转换为map，兼容RESP2扁平数组与RESP3字典两种返回格式
+func replyToMap(result interface{}) map[string]interface{} {
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return resultMap
+	}
+
+	values := cast.ToSlice(result)
+	resultMap := make(map[string]interface{}, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		resultMap[cast.ToString(values[i])] = values[i+1]
+	}
+	return resultMap
+}
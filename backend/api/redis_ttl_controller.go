@@ -0,0 +1,353 @@
+package api
+
+import (
+	"context"
+	"ev-plugin/backend/dto"
+	"ev-plugin/backend/job"
+	"ev-plugin/backend/response"
+	"ev-plugin/backend/vo"
+	"fmt"
+	"sync"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+	"github.com/1340691923/eve-plugin-sdk-go/util"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxTtlSampleSize = 10000 // RANDOMKEY采样数量上限
+
+	ttlHourMs = int64(3600 * 1000)
+	ttlDayMs  = 24 * ttlHourMs
+)
+
+// ttlBuckets TTL分布区间，按上限升序，上限为0表示不封顶；永不过期的Key单独成桶
+var ttlBuckets = []struct {
+	label string
+	maxMs int64
+}{
+	{"<1m", 60 * 1000},
+	{"1m-1h", ttlHourMs},
+	{"1h-1d", ttlDayMs},
+	{"1d-7d", 7 * ttlDayMs},
+	{"7d-30d", 30 * ttlDayMs},
+	{">=30d", 0},
+}
+
+// GetTtlAnalysisAction TTL分布分析 - 按游标分页扫描或RANDOMKEY随机采样，统计TTL直方图与即将释放的内存
+func (this *RedisController) GetTtlAnalysisAction(ctx *gin.Context) {
+	req := new(dto.RedisTtlAnalysisRequest)
+	err := ctx.BindJSON(req)
+	if err != nil {
+		this.Error(ctx, err)
+		return
+	}
+
+	// 设置默认值
+	if req.Pattern == "" {
+		req.Pattern = "*"
+	}
+	if req.Count <= 0 {
+		req.Count = 1000
+	}
+	if req.Cursor == "" {
+		req.Cursor = "0"
+	}
+	if req.SampleSize > maxTtlSampleSize {
+		req.SampleSize = maxTtlSampleSize
+	}
+	// RANDOMKEY无法按模式采样，过滤后的样本也不能按DBSIZE外推，采样时不支持模式
+	if req.SampleSize > 0 && req.Pattern != "*" {
+		this.Error(ctx, fmt.Errorf("随机采样不支持按模式过滤，请改用扫描模式"))
+		return
+	}
+	normalizeTtlAnalysisOptions(&req.RedisTtlAnalysisOptions)
+
+	logger.DefaultLogger.Debug("开始Redis TTL分布分析",
+		"conn_id:", req.EsConnect,
+		"database:", req.Database,
+		"pattern:", req.Pattern,
+		"cursor:", req.Cursor,
+		"sample_size:", req.SampleSize)
+
+	// 调用基座API
+	api := ev_api.NewEvWrapApi(req.EsConnect, util.GetEvUserID(ctx))
+
+	resp := vo.RedisTtlAnalysisResponse{NextCursor: "0"}
+	if dbSizeResult, err := this.executeRedisCommandWithRetry(ctx, api, req.Database, "DBSIZE"); err == nil && dbSizeResult != nil {
+		resp.DbSize = cast.ToInt64(dbSizeResult)
+	}
+
+	var keys []string
+	if req.SampleSize > 0 {
+		resp.Mode = "sample"
+		keys, err = this.randomKeys(ctx, api, req.Database, req.SampleSize)
+	} else {
+		resp.Mode = "scan"
+		keys, resp.NextCursor, err = this.scanKeysPage(ctx, api, req.Database, req.Cursor, req.Pattern, req.Count, nil)
+	}
+	if err != nil {
+		logger.DefaultLogger.Error("获取待分析Key失败", "error:", err)
+		this.Error(ctx, err)
+		return
+	}
+
+	stats := newTtlStats(req.TopN)
+	stats.addKeys(this.analyzeKeysTtl(ctx, api, req.Database, keys))
+	resp.RedisTtlAnalysisResult = stats.result()
+
+	// 随机采样近似均匀分布，可按DBSIZE外推整个库的释放量
+	if resp.Mode == "sample" && resp.ScannedKeys > 0 && resp.DbSize > 0 {
+		ratio := float64(resp.DbSize) / float64(resp.ScannedKeys)
+		resp.EstimatedWithinHour = scaleTtlForecast(resp.ExpireWithinHour, ratio)
+		resp.EstimatedWithinDay = scaleTtlForecast(resp.ExpireWithinDay, ratio)
+	}
+
+	this.Success(ctx, response.SearchSuccess, resp)
+}
+
+// ttlAnalysisJob TTL分布分析任务 - 扫描整个keyspace，汇总TTL直方图与最大的永不过期Key
+func (this *RedisController) ttlAnalysisJob(ctx context.Context, j *job.Job) (interface{}, error) {
+	params := new(dto.RedisTtlAnalysisJobParams)
+	if err := j.BindParams(params); err != nil {
+		return nil, err
+	}
+	if params.Pattern == "" {
+		params.Pattern = "*"
+	}
+	normalizeTtlAnalysisOptions(&params.RedisTtlAnalysisOptions)
+
+	api := ev_api.NewEvWrapApi(j.EsConnect, j.UserId)
+
+	if dbSizeResult, err := this.executeRedisCommandWithRetry(ctx, api, j.Database, "DBSIZE"); err == nil && dbSizeResult != nil {
+		j.SetTotal(cast.ToInt64(dbSizeResult))
+	}
+
+	stats := newTtlStats(params.TopN)
+
+	cursor := "0"
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		nextCursor, keys, err := this.scanKeys(ctx, api, j.Database, cursor, params.Pattern, maxScanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		infos, failed := this.analyzeKeysTtl(ctx, api, j.Database, keys)
+		stats.addKeys(infos, failed)
+
+		j.AddProcessed(int64(len(keys)))
+		j.AddFailed(int64(failed))
+		j.SetMessage(fmt.Sprintf("已分析%d个Key，其中%d个永不过期", stats.scanned, stats.noExpiryKeys))
+
+		cursor = nextCursor
+		if cursor == "0" {
+			break
+		}
+	}
+
+	return &vo.RedisTtlAnalysisJobResult{
+		Pattern:                params.Pattern,
+		RedisTtlAnalysisResult: stats.result(),
+	}, nil
+}
+
+// randomKeys 并发执行RANDOMKEY采样，返回去重后的key
+func (this *RedisController) randomKeys(ctx context.Context, api *ev_api.EvApiAdapter, database int, sampleSize int) ([]string, error) {
+	seen := make(map[string]bool, sampleSize)
+	var mu sync.Mutex // 保护共享数据
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for i := 0; i < sampleSize; i++ {
+		g.Go(func() error {
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, "RANDOMKEY")
+			if err != nil {
+				return fmt.Errorf("RANDOMKEY执行失败: %w", err)
+			}
+			if result == nil {
+				return nil // 空库
+			}
+			mu.Lock()
+			seen[cast.ToString(result)] = true
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ttlKeyInfo 单个key的TTL与内存信息
+type ttlKeyInfo struct {
+	vo.RedisKeyMemoryInfo
+	pttl int64 // 剩余毫秒数，-1表示永不过期
+}
+
+// analyzeKeysTtl 并发获取一批key的PTTL与内存大小，返回成功分析的结果与失败数量；已过期或被删除的key直接忽略
+func (this *RedisController) analyzeKeysTtl(ctx context.Context, api *ev_api.EvApiAdapter, database int, keys []string) ([]ttlKeyInfo, int) {
+	infos := make([]ttlKeyInfo, 0, len(keys))
+	failed := 0
+	var mu sync.Mutex // 保护共享数据
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(memoryAnalysisConcurrency)
+
+	for _, key := range keys {
+		key := key // 避免闭包问题
+		g.Go(func() error {
+			result, err := this.executeRedisCommandWithRetry(gctx, api, database, "PTTL", key)
+			var pttl int64
+			if err == nil {
+				pttl = cast.ToInt64(result)
+				if pttl == -2 {
+					return nil // key已过期或被删除
+				}
+			}
+
+			var keyInfo vo.RedisKeyMemoryInfo
+			if err == nil {
+				// 优先使用MEMORY USAGE（默认采样），不支持时退化为估算
+				keyInfo, err = this.analyzeKeyMemoryOfficial(gctx, api, database, key)
+				if err != nil {
+					keyInfo, err = this.analyzeKeyMemoryFast(gctx, api, database, key)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.DefaultLogger.Debug("分析Key TTL失败", "key:", key, "error:", err)
+				failed++
+				return nil
+			}
+
+			keyInfo.TTL = -1
+			if pttl >= 0 {
+				keyInfo.TTL = pttl / 1000
+			}
+			infos = append(infos, ttlKeyInfo{RedisKeyMemoryInfo: keyInfo, pttl: pttl})
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return infos, failed
+}
+
+// normalizeTtlAnalysisOptions 填充TTL分析选项的默认值
+func normalizeTtlAnalysisOptions(options *dto.RedisTtlAnalysisOptions) {
+	if options.TopN <= 0 {
+		options.TopN = 50
+	}
+}
+
+func scaleTtlForecast(forecast vo.RedisTtlForecast, ratio float64) vo.RedisTtlForecast {
+	return vo.RedisTtlForecast{
+		Keys:  int64(float64(forecast.Keys) * ratio),
+		Bytes: int64(float64(forecast.Bytes) * ratio),
+	}
+}
+
+// ttlStats 累计TTL直方图、释放预测与最大的永不过期Key
+type ttlStats struct {
+	topN         int
+	scanned      int64
+	failed       int64
+	totalSize    int64
+	expiringKeys int64
+	noExpiryKeys int64
+	noExpirySize int64
+	sumTtlMs     int64
+	buckets      []vo.RedisTtlBucket
+	withinHour   vo.RedisTtlForecast
+	withinDay    vo.RedisTtlForecast
+	topNoExpiry  []vo.RedisKeyMemoryInfo
+}
+
+func newTtlStats(topN int) *ttlStats {
+	buckets := make([]vo.RedisTtlBucket, 0, len(ttlBuckets)+1)
+	buckets = append(buckets, vo.RedisTtlBucket{Label: "no_expiry", MaxSeconds: -1})
+	for _, bucket := range ttlBuckets {
+		buckets = append(buckets, vo.RedisTtlBucket{Label: bucket.label, MaxSeconds: bucket.maxMs / 1000})
+	}
+	return &ttlStats{topN: topN, buckets: buckets}
+}
+
+func (this *ttlStats) addKeys(infos []ttlKeyInfo, failed int) {
+	this.failed += int64(failed)
+	for _, info := range infos {
+		this.add(info)
+	}
+	// 定期截断，避免TopN候选集无限增长
+	if len(this.topNoExpiry) > this.topN*2 {
+		this.topNoExpiry = topKeysBySize(this.topNoExpiry, this.topN)
+	}
+}
+
+func (this *ttlStats) add(info ttlKeyInfo) {
+	this.scanned++
+	this.totalSize += info.SizeBytes
+
+	if info.pttl < 0 {
+		this.noExpiryKeys++
+		this.noExpirySize += info.SizeBytes
+		this.buckets[0].Count++
+		this.buckets[0].TotalSize += info.SizeBytes
+		this.topNoExpiry = append(this.topNoExpiry, info.RedisKeyMemoryInfo)
+		return
+	}
+
+	this.expiringKeys++
+	this.sumTtlMs += info.pttl
+	for i, bucket := range ttlBuckets {
+		if bucket.maxMs == 0 || info.pttl < bucket.maxMs {
+			this.buckets[i+1].Count++
+			this.buckets[i+1].TotalSize += info.SizeBytes
+			break
+		}
+	}
+	if info.pttl <= ttlHourMs {
+		this.withinHour.Keys++
+		this.withinHour.Bytes += info.SizeBytes
+	}
+	if info.pttl <= ttlDayMs {
+		this.withinDay.Keys++
+		this.withinDay.Bytes += info.SizeBytes
+	}
+}
+
+func (this *ttlStats) result() vo.RedisTtlAnalysisResult {
+	result := vo.RedisTtlAnalysisResult{
+		ScannedKeys:      this.scanned,
+		FailedKeys:       this.failed,
+		TotalSize:        this.totalSize,
+		ExpiringKeys:     this.expiringKeys,
+		NoExpiryKeys:     this.noExpiryKeys,
+		NoExpirySize:     this.noExpirySize,
+		Buckets:          this.buckets,
+		ExpireWithinHour: this.withinHour,
+		ExpireWithinDay:  this.withinDay,
+		TopNoExpiryKeys:  topKeysBySize(this.topNoExpiry, this.topN),
+	}
+	if this.expiringKeys > 0 {
+		result.AvgTtlSeconds = this.sumTtlMs / this.expiringKeys / 1000
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "encoding/json"
+
+// 启动后台任务请求DTO
+type JobStartRequest struct {
+	EsConnect int             `json:"es_connect"` // 数据源连接ID
+	Database  int             `json:"database"`   // Redis数据库索引，默认为0
+	Kind      string          `json:"kind"`       // 任务类型 (memory_analysis, big_keys)
+	Params    json.RawMessage `json:"params"`     // 任务参数，结构由任务类型决定
+}
+
+// 后台任务ID请求DTO (状态/取消/结果)
+type JobIdRequest struct {
+	JobId string `json:"job_id"` // 任务ID
+}
+
+// 后台任务列表请求DTO
+type JobListRequest struct {
+	Kind string `json:"kind"` // 任务类型，为空表示全部
+}
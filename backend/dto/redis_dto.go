@@ -27,8 +27,14 @@ type RedisMemoryAnalysisRequest struct {
 	EsConnect int    `json:"es_connect"` // 数据源连接ID
 	Database  int    `json:"database"`   // Redis数据库索引，默认为0
 	Pattern   string `json:"pattern"`    // Key匹配模式，默认为*
-	Count     int    `json:"count"`      // 单次返回数量，默认为50
-	Cursor    string `json:"cursor"`     // 游标，用于分页
+	Count     int    `json:"count"`      // 单次返回数量（近似值），默认为50
+	Cursor    string `json:"cursor"`     // SCAN游标，首次传"0"，之后传上一页返回的nextCursor
+}
+
+// Redis全量内存分析后台任务参数 (任务类型 memory_analysis)
+type RedisMemoryAnalysisJobParams struct {
+	Pattern string `json:"pattern"` // Key匹配模式，默认为*
+	TopN    int    `json:"top_n"`   // 结果中保留的最大Key数量，默认为100
 }
 
 // Redis Key删除请求DTO
@@ -38,11 +44,43 @@ type RedisDeleteKeyRequest struct {
 	Key       string `json:"key"`        // 要删除的Key
 }
 
+// Redis Key重命名请求DTO
+type RedisRenameKeyRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // 原Key
+	NewKey    string `json:"new_key"`    // 新Key
+	Overwrite bool   `json:"overwrite"`  // 新Key已存在时是否覆盖，默认不覆盖并返回冲突错误
+}
+
+// Redis Key复制请求DTO
+type RedisCopyKeyRequest struct {
+	EsConnect    int    `json:"es_connect"`    // 数据源连接ID
+	Database     int    `json:"database"`      // 源Key所在的库，默认为0
+	Key          string `json:"key"`           // 源Key
+	NewKey       string `json:"new_key"`       // 目标Key，为空时与源Key同名
+	DestDatabase *int   `json:"dest_database"` // 目标库，为空时为源Key所在的库
+	Replace      bool   `json:"replace"`       // 目标Key已存在时是否覆盖，默认不覆盖并返回冲突错误
+}
+
+// Redis Key移动到其他库请求DTO
+type RedisMoveKeyRequest struct {
+	EsConnect    int    `json:"es_connect"`    // 数据源连接ID
+	Database     int    `json:"database"`      // 源Key所在的库，默认为0
+	Key          string `json:"key"`           // 要移动的Key
+	DestDatabase int    `json:"dest_database"` // 目标库
+	Replace      bool   `json:"replace"`       // 目标库已存在同名Key时是否覆盖，默认不覆盖并返回冲突错误
+}
+
 // Redis Key详情请求DTO
 type RedisKeyDetailRequest struct {
 	EsConnect int    `json:"es_connect"` // 数据源连接ID
 	Database  int    `json:"database"`   // Redis数据库索引，默认为0
 	Key       string `json:"key"`        // 要查询的Key
+	Cursor    string `json:"cursor"`     // 分页游标，使用上一页返回的nextCursor：hash/set为HSCAN/SSCAN游标，list/zset为偏移量，stream为起始entry ID（包含），为空表示从头开始
+	Offset    int    `json:"offset"`     // list/zset的起始偏移量，cursor为空时生效，用于直接跳页
+	PageSize  int    `json:"page_size"`  // 每页元素数量，默认为100，最大为1000
+	Reverse   bool   `json:"reverse"`    // stream是否按ID倒序读取（XREVRANGE）
 }
 
 // Redis Key保存请求DTO
@@ -50,18 +88,20 @@ type RedisSetKeyRequest struct {
 	EsConnect int         `json:"es_connect"` // 数据源连接ID
 	Database  int         `json:"database"`   // Redis数据库索引，默认为0
 	Key       string      `json:"key"`        // Key名称
-	Type      string      `json:"type"`       // 数据类型 (string, hash, list, set, zset)
+	Type      string      `json:"type"`       // 数据类型 (string, hash, list, set, zset, stream)
 	TTL       int64       `json:"ttl"`        // 过期时间（秒），-1表示永不过期
 	Value     interface{} `json:"value"`      // 值，根据类型不同而不同
+	Version   string      `json:"version"`    // 读取Key详情时返回的版本号，不为空时只有Key未被修改才会保存；none表示要求Key不存在
 }
 
 // Redis Key搜索请求DTO (后端搜索)
 type RedisSearchKeysRequest struct {
 	EsConnect     int    `json:"es_connect"`     // 数据源连接ID
 	Database      int    `json:"database"`       // Redis数据库索引，默认为0
-	SearchText    string `json:"search_text"`    // 搜索文本，使用 strings.Contains 匹配
-	Count         int    `json:"count"`          // 单次返回数量，默认为50
-	Cursor        string `json:"cursor"`         // 游标，用于分页
+	SearchText    string `json:"search_text"`    // 搜索文本，含义由match_mode决定
+	MatchMode     string `json:"match_mode"`     // 匹配模式 (contains, glob, prefix, regex)，默认contains
+	Count         int    `json:"count"`          // 单次返回数量（近似值），默认为50
+	Cursor        string `json:"cursor"`         // SCAN游标，首次传"0"，之后传上一页返回的nextCursor
 	CaseSensitive bool   `json:"case_sensitive"` // 是否区分大小写，默认false
 }
 
@@ -77,3 +117,415 @@ type RedisBatchMemoryAnalysisRequest struct {
 	Database  int      `json:"database"`   // Redis数据库索引，默认为0
 	Keys      []string `json:"keys"`       // 要分析的key数组
 }
+
+// Redis Key命名空间树请求DTO - 按分隔符把key聚合成前缀树，支持按前缀懒加载子节点
+type RedisNamespaceTreeRequest struct {
+	EsConnect   int    `json:"es_connect"`    // 数据源连接ID
+	Database    int    `json:"database"`      // Redis数据库索引，默认为0
+	Delimiter   string `json:"delimiter"`     // 命名空间分隔符，默认为:
+	Prefix      string `json:"prefix"`        // 父节点前缀（需包含末尾分隔符），为空表示根节点
+	MemoryMode  string `json:"memory_mode"`   // 内存统计方式 (none, sampled, exact)，默认sampled
+	SampleSize  int    `json:"sample_size"`   // sampled模式下每个节点采样的key数量，默认20
+	MaxScanKeys int    `json:"max_scan_keys"` // 单次请求最多扫描的key数量，默认100000，exact模式最多5000
+	MaxLeafKeys int    `json:"max_leaf_keys"` // 单次请求最多返回的叶子key数量，默认500
+	Cursor      string `json:"cursor"`        // SCAN游标，首次传"0"，扫描未完成时传上次返回的nextCursor继续统计
+}
+
+// Redis大Key判定条件
+type RedisBigKeyOptions struct {
+	ThresholdBytes    int64            `json:"threshold_bytes"`    // 内存阈值（字节），默认1MB
+	ThresholdElements map[string]int64 `json:"threshold_elements"` // 各类型元素数量阈值，如{"hash":5000}，未配置的类型默认5000；支持hash/list/set/zset/stream，string只按内存阈值判定
+	TopN              int              `json:"top_n"`              // 每种类型保留的最大Key数量，默认10
+}
+
+// Redis大Key分析请求DTO - 按游标分页扫描
+type RedisBigKeysRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Pattern   string `json:"pattern"`    // Key匹配模式，默认为*
+	Count     int    `json:"count"`      // 单页扫描的Key数量（近似值），默认为1000
+	Cursor    string `json:"cursor"`     // SCAN游标，首次传"0"，之后传上一页返回的nextCursor
+	RedisBigKeyOptions
+}
+
+// Redis大Key分析后台任务参数 (任务类型 big_keys)
+type RedisBigKeysJobParams struct {
+	Pattern    string `json:"pattern"`      // Key匹配模式，默认为*
+	MaxBigKeys int    `json:"max_big_keys"` // 结果中保留的超过阈值的Key数量上限，默认1000
+	RedisBigKeyOptions
+}
+
+// Redis大Key分析结果导出请求DTO
+type RedisBigKeysExportRequest struct {
+	JobId string `json:"job_id"` // big_keys任务ID
+}
+
+// Redis TTL分布分析选项
+type RedisTtlAnalysisOptions struct {
+	TopN int `json:"top_n"` // 返回的最大永不过期Key数量，默认50
+}
+
+// Redis TTL分布分析请求DTO - 按游标分页扫描或随机采样
+type RedisTtlAnalysisRequest struct {
+	EsConnect  int    `json:"es_connect"`  // 数据源连接ID
+	Database   int    `json:"database"`    // Redis数据库索引，默认为0
+	Pattern    string `json:"pattern"`     // Key匹配模式，默认为*（扫描模式）
+	Count      int    `json:"count"`       // 单页扫描的Key数量（近似值），默认为1000（扫描模式）
+	Cursor     string `json:"cursor"`      // SCAN游标，首次传"0"，之后传上一页返回的nextCursor（扫描模式）
+	SampleSize int    `json:"sample_size"` // 大于0时改用RANDOMKEY随机采样，最多10000个，不能与pattern同时使用，忽略cursor
+	RedisTtlAnalysisOptions
+}
+
+// Redis TTL分布分析后台任务参数 (任务类型 ttl_analysis)
+type RedisTtlAnalysisJobParams struct {
+	Pattern string `json:"pattern"` // Key匹配模式，默认为*
+	RedisTtlAnalysisOptions
+}
+
+// Redis热Key分析请求DTO
+type RedisHotKeysRequest struct {
+	EsConnect  int    `json:"es_connect"`  // 数据源连接ID
+	Database   int    `json:"database"`    // Redis数据库索引，默认为0
+	Mode       string `json:"mode"`        // 分析方式 (auto, lfu, idle, monitor)，默认auto：LFU淘汰策略下用lfu，否则用monitor，未提供monitor_log时报错（插件无法在线执行MONITOR）
+	Pattern    string `json:"pattern"`     // Key匹配模式，默认为*（lfu/idle模式）
+	Count      int    `json:"count"`       // 单次扫描的Key数量（近似值），默认为10000（lfu/idle模式）
+	Cursor     string `json:"cursor"`      // SCAN游标，首次传"0"，之后传上次返回的nextCursor（lfu/idle模式）
+	TopN       int    `json:"top_n"`       // 返回的热Key数量，默认为50
+	MonitorLog string `json:"monitor_log"` // redis-cli monitor采集到的输出（monitor模式，auto模式非LFU策略时必填）
+}
+
+// Redis Stream追加消息请求DTO (XADD)
+type RedisStreamAddRequest struct {
+	EsConnect   int         `json:"es_connect"`  // 数据源连接ID
+	Database    int         `json:"database"`    // Redis数据库索引，默认为0
+	Key         string      `json:"key"`         // Stream Key
+	Id          string      `json:"id"`          // 消息ID，默认为*自动生成
+	Fields      interface{} `json:"fields"`      // 消息字段，按顺序的[{field, value}]数组
+	MaxLen      int64       `json:"maxlen"`      // 追加时按MAXLEN裁剪，0表示不裁剪
+	Approximate bool        `json:"approximate"` // 裁剪时是否使用~近似裁剪
+}
+
+// Redis Stream裁剪请求DTO (XTRIM)
+type RedisStreamTrimRequest struct {
+	EsConnect   int    `json:"es_connect"`  // 数据源连接ID
+	Database    int    `json:"database"`    // Redis数据库索引，默认为0
+	Key         string `json:"key"`         // Stream Key
+	Strategy    string `json:"strategy"`    // 裁剪策略 (MAXLEN, MINID)
+	Threshold   string `json:"threshold"`   // MAXLEN为保留的最大长度，MINID为保留的最小ID
+	Approximate bool   `json:"approximate"` // 是否使用~近似裁剪
+}
+
+// Redis Stream删除消息请求DTO (XDEL)
+type RedisStreamDeleteRequest struct {
+	EsConnect int      `json:"es_connect"` // 数据源连接ID
+	Database  int      `json:"database"`   // Redis数据库索引，默认为0
+	Key       string   `json:"key"`        // Stream Key
+	Ids       []string `json:"ids"`        // 要删除的消息ID
+}
+
+// Redis Stream消费组请求DTO (XGROUP CREATE / DESTROY)
+type RedisStreamGroupRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // Stream Key
+	Group     string `json:"group"`      // 消费组名称
+	Id        string `json:"id"`         // 创建时的起始ID，默认为$（只消费新消息）
+	MkStream  bool   `json:"mkstream"`   // Stream不存在时是否自动创建
+}
+
+// Redis Hash设置字段请求DTO (HSET / HSETNX)
+type RedisHashSetRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // Hash Key
+	Field     string `json:"field"`      // 字段名
+	Value     string `json:"value"`      // 字段值
+	OnlyNew   bool   `json:"only_new"`   // 是否仅在字段不存在时设置（HSETNX）
+}
+
+// Redis Hash删除字段请求DTO (HDEL)
+type RedisHashDeleteRequest struct {
+	EsConnect int      `json:"es_connect"` // 数据源连接ID
+	Database  int      `json:"database"`   // Redis数据库索引，默认为0
+	Key       string   `json:"key"`        // Hash Key
+	Fields    []string `json:"fields"`     // 要删除的字段
+}
+
+// Redis List按下标设置元素请求DTO (LSET)
+type RedisListSetRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // List Key
+	Index     int64  `json:"index"`      // 元素下标，支持负数
+	Value     string `json:"value"`      // 新的元素值
+}
+
+// Redis List插入元素请求DTO (LINSERT / LPUSH / RPUSH)
+type RedisListInsertRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // List Key
+	Position  string `json:"position"`   // 插入位置 (BEFORE, AFTER, HEAD, TAIL)，默认为TAIL
+	Pivot     string `json:"pivot"`      // BEFORE/AFTER时的参照元素
+	Value     string `json:"value"`      // 插入的元素值
+}
+
+// Redis List删除元素请求DTO (LREM)
+type RedisListRemoveRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Key       string `json:"key"`        // List Key
+	Value     string `json:"value"`      // 要删除的元素值
+	Count     int64  `json:"count"`      // 同LREM的count：大于0从头删除，小于0从尾删除，0删除全部
+	Index     *int64 `json:"index"`      // 指定时只删除该下标上的元素，且要求该元素仍等于value
+}
+
+// Redis Set成员请求DTO (SADD / SREM)
+type RedisSetMembersRequest struct {
+	EsConnect int      `json:"es_connect"` // 数据源连接ID
+	Database  int      `json:"database"`   // Redis数据库索引，默认为0
+	Key       string   `json:"key"`        // Set Key
+	Members   []string `json:"members"`    // 成员列表
+}
+
+// Redis ZSet成员及分数
+type RedisZSetMember struct {
+	Member string  `json:"member"` // 成员
+	Score  float64 `json:"score"`  // 分数
+}
+
+// Redis ZSet添加成员请求DTO (ZADD)
+type RedisZSetAddRequest struct {
+	EsConnect int               `json:"es_connect"` // 数据源连接ID
+	Database  int               `json:"database"`   // Redis数据库索引，默认为0
+	Key       string            `json:"key"`        // ZSet Key
+	Members   []RedisZSetMember `json:"members"`    // 成员及分数
+	Condition string            `json:"condition"`  // 写入条件 (NX, XX, GT, LT)，为空表示无条件
+}
+
+// Redis ZSet增加分数请求DTO (ZINCRBY)
+type RedisZSetIncrByRequest struct {
+	EsConnect int     `json:"es_connect"` // 数据源连接ID
+	Database  int     `json:"database"`   // Redis数据库索引，默认为0
+	Key       string  `json:"key"`        // ZSet Key
+	Member    string  `json:"member"`     // 成员
+	Increment float64 `json:"increment"`  // 增量，可为负数
+}
+
+// Redis ZSet删除成员请求DTO (ZREM)
+type RedisZSetRemoveRequest struct {
+	EsConnect int      `json:"es_connect"` // 数据源连接ID
+	Database  int      `json:"database"`   // Redis数据库索引，默认为0
+	Key       string   `json:"key"`        // ZSet Key
+	Members   []string `json:"members"`    // 要删除的成员
+}
+
+// Redis批量操作的Key匹配条件
+type RedisKeyPatternOptions struct {
+	Pattern       string `json:"pattern"`        // 匹配文本，含义由match_mode决定
+	MatchMode     string `json:"match_mode"`     // 匹配模式 (glob, prefix, contains, regex)，默认glob
+	CaseSensitive bool   `json:"case_sensitive"` // 是否区分大小写，默认false
+}
+
+// Redis按模式批量删除预览请求DTO - 只扫描不删除
+type RedisBulkDeletePreviewRequest struct {
+	EsConnect  int `json:"es_connect"`  // 数据源连接ID
+	Database   int `json:"database"`    // Redis数据库索引，默认为0
+	SampleSize int `json:"sample_size"` // 返回的样例Key数量，默认100
+	RedisKeyPatternOptions
+}
+
+// Redis按模式批量删除参数 (任务类型 bulk_delete)
+type RedisBulkDeleteParams struct {
+	BatchSize     int   `json:"batch_size"`      // 每批UNLINK的Key数量，默认100
+	KeysPerSecond int   `json:"keys_per_second"` // 每秒最多删除的Key数量，默认1000
+	Expected      int64 `json:"expected"`        // 预览得到的匹配数量，用于进度展示；匹配数量远超该值时任务停止
+	ConfirmAll    bool  `json:"confirm_all"`     // 确认删除所有Key，匹配模式会匹配所有Key（如*、.*）时必须为true
+	RedisKeyPatternOptions
+}
+
+// Redis按模式批量删除请求DTO
+type RedisBulkDeleteRequest struct {
+	EsConnect int `json:"es_connect"` // 数据源连接ID
+	Database  int `json:"database"`   // Redis数据库索引，默认为0
+	RedisBulkDeleteParams
+}
+
+// Redis批量设置过期时间参数 (任务类型 bulk_expire)
+type RedisBulkExpireParams struct {
+	Command       string   `json:"command"`         // 过期命令 (EXPIRE, PEXPIRE, EXPIREAT, PERSIST)
+	Value         int64    `json:"value"`           // EXPIRE为秒数，PEXPIRE为毫秒数，EXPIREAT为Unix时间戳（秒），PERSIST忽略
+	Condition     string   `json:"condition"`       // 生效条件 (NX, XX, GT, LT)，需要Redis 7.0+，为空表示无条件
+	Keys          []string `json:"keys"`            // 显式指定的Key列表，不为空时忽略匹配条件并同步执行
+	BatchSize     int      `json:"batch_size"`      // 按模式执行时每批的Key数量，默认100
+	KeysPerSecond int      `json:"keys_per_second"` // 按模式执行时每秒最多处理的Key数量，默认1000
+	Expected      int64    `json:"expected"`        // 预览得到的匹配数量，仅用于进度展示
+	RedisKeyPatternOptions
+}
+
+// Redis批量设置过期时间请求DTO
+type RedisBulkExpireRequest struct {
+	EsConnect int `json:"es_connect"` // 数据源连接ID
+	Database  int `json:"database"`   // Redis数据库索引，默认为0
+	RedisBulkExpireParams
+}
+
+// Redis跨数据源迁移任务参数 (任务类型 migrate)，源为任务所在的连接与库
+type RedisMigrateParams struct {
+	TargetConnect  int      `json:"target_connect"`  // 目标数据源连接ID
+	TargetDatabase int      `json:"target_database"` // 目标库
+	Keys           []string `json:"keys"`            // 指定要迁移的Key列表，非空时忽略匹配条件
+	Replace        bool     `json:"replace"`         // 目标Key已存在时是否覆盖，默认跳过
+	BatchSize      int      `json:"batch_size"`      // 每批迁移的Key数量，默认100，最大1000
+	KeysPerSecond  int      `json:"keys_per_second"` // 每秒最多迁移的Key数量，默认1000
+	Expected       int64    `json:"expected"`        // 按模式迁移时预计的Key数量（可取自预览结果），用于计算进度
+	RedisKeyPatternOptions
+}
+
+// Redis跨数据源迁移请求DTO
+type RedisMigrateRequest struct {
+	EsConnect int `json:"es_connect"` // 源数据源连接ID
+	Database  int `json:"database"`   // 源库
+	RedisMigrateParams
+}
+
+// Redis keyspace对比任务参数 (任务类型 keyspace_diff)，一侧为任务所在的连接与库
+type RedisKeyspaceDiffParams struct {
+	TargetConnect  int    `json:"target_connect"`  // 对比的数据源连接ID，可与当前连接相同
+	TargetDatabase int    `json:"target_database"` // 对比的库
+	DigestMode     string `json:"digest_mode"`     // 值摘要方式 (dump, content, none)，默认dump；跨版本对比建议用content
+	MaxKeys        int    `json:"max_keys"`        // 每类差异在结果中保留的Key数量，默认1000
+	RedisKeyPatternOptions
+}
+
+// Redis keyspace对比请求DTO
+type RedisKeyspaceDiffRequest struct {
+	EsConnect int `json:"es_connect"` // 数据源连接ID
+	Database  int `json:"database"`   // Redis数据库索引，默认为0
+	RedisKeyspaceDiffParams
+}
+
+// Redis Key导出请求DTO (JSON Lines)
+type RedisExportRequest struct {
+	EsConnect int      `json:"es_connect"` // 数据源连接ID
+	Database  int      `json:"database"`   // Redis数据库索引，默认为0
+	Keys      []string `json:"keys"`       // 指定要导出的Key列表，非空时忽略匹配条件
+	RedisKeyPatternOptions
+}
+
+// Redis Key导入任务参数 (任务类型 import)
+type RedisImportParams struct {
+	Policy    string `json:"policy"`     // Key已存在时的处理策略 (skip, overwrite, fail)，默认skip
+	DryRun    bool   `json:"dry_run"`    // 只校验格式并统计冲突，不写入数据
+	ContentId string `json:"content_id"` // 导入内容在插件内存中的ID，由接口生成
+}
+
+// Redis Key导入请求DTO (JSON Lines，每行包含key、type、ttl、value，与导出格式一致)
+type RedisImportRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Content   string `json:"content"`    // JSON Lines文件内容，最大64MB
+	RedisImportParams
+}
+
+// Redis命令文件导入任务参数 (任务类型 command_import)
+type RedisCommandImportParams struct {
+	Format          string   `json:"format"`           // 文件格式 (auto, resp, inline)，默认auto
+	AllowedCommands []string `json:"allowed_commands"` // 允许执行的命令，只能在内置白名单内进一步收窄，为空表示使用内置白名单
+	ContentId       string   `json:"content_id"`       // 导入内容在插件内存中的ID，由接口生成
+}
+
+// Redis命令文件导入请求DTO (RESP或redis-cli命令脚本)
+type RedisCommandImportRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0，文件中不允许SELECT
+	Content   string `json:"content"`    // 文件内容，最大64MB
+	RedisCommandImportParams
+}
+
+// Redis Key快照任务参数 (任务类型 snapshot)
+type RedisSnapshotParams struct {
+	SnapshotId string   `json:"snapshot_id"` // 快照ID，由接口生成
+	Keys       []string `json:"keys"`        // 指定要快照的Key列表，非空时忽略匹配条件
+	MaxKeys    int      `json:"max_keys"`    // 快照最多包含的Key数量，超过时快照失败，默认且最大100000
+	RedisKeyPatternOptions
+}
+
+// Redis Key快照创建请求DTO
+type RedisSnapshotCreateRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Name      string `json:"name"`       // 快照名称，为空时按时间生成
+	RedisSnapshotParams
+}
+
+// Redis Key快照恢复任务参数 (任务类型 snapshot_restore)，恢复到快照所在的连接与库
+type RedisSnapshotRestoreParams struct {
+	SnapshotId string `json:"snapshot_id"` // 快照ID
+}
+
+// Redis Key快照ID请求DTO
+type RedisSnapshotIdRequest struct {
+	SnapshotId string `json:"snapshot_id"` // 快照ID
+}
+
+// Redis Key快照列表请求DTO
+type RedisSnapshotListRequest struct {
+	EsConnect int `json:"es_connect"` // 数据源连接ID，为0表示全部
+	Limit     int `json:"limit"`      // 返回数量，默认100
+}
+
+// Redis Key快照详情请求DTO
+type RedisSnapshotDetailRequest struct {
+	SnapshotId string `json:"snapshot_id"` // 快照ID
+	AfterId    int64  `json:"after_id"`    // 分页游标，取上一页返回的nextAfterId，首页为0
+	Limit      int    `json:"limit"`       // 每页Key数量，默认100，最大1000
+}
+
+// Redis RDB离线分析任务参数 (任务类型 rdb_analysis)
+type RedisRdbAnalysisParams struct {
+	Path        string `json:"path"`         // 插件所在机器上RDB文件的绝对路径，与上传文件二选一
+	UploadId    string `json:"upload_id"`    // 上传文件在插件临时目录中的ID，由接口生成
+	Delimiter   string `json:"delimiter"`    // 前缀聚合的分隔符，默认为:
+	PrefixDepth int    `json:"prefix_depth"` // 前缀聚合的层数，默认2，最大5
+	TopN        int    `json:"top_n"`        // 按大小与元素数量分别返回的最大Key数量，默认100
+	MaxPrefixes int    `json:"max_prefixes"` // 返回的前缀数量，按大小倒序，默认500
+}
+
+// Redis RDB离线分析请求DTO，上传文件时以multipart提交file与params（本结构的JSON）
+type RedisRdbAnalysisRequest struct {
+	EsConnect int `json:"es_connect"` // 数据源连接ID，仅用于记录任务归属，分析过程不访问Redis
+	RedisRdbAnalysisParams
+}
+
+// Redis慢日志请求DTO
+type RedisSlowlogRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Count     int    `json:"count"`      // 读取的条数，默认128，最大10000
+	Delimiter string `json:"delimiter"`  // 归一化key模式时的分隔符，默认为:
+}
+
+// Redis客户端列表请求DTO
+type RedisClientListRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Type      string `json:"type"`       // 客户端类型 (normal, master, replica, pubsub)，为空表示全部
+}
+
+// Redis断开客户端连接请求DTO，id、addr、user三选一
+type RedisClientKillRequest struct {
+	EsConnect int    `json:"es_connect"` // 数据源连接ID
+	Database  int    `json:"database"`   // Redis数据库索引，默认为0
+	Id        int64  `json:"id"`         // 客户端ID
+	Addr      string `json:"addr"`       // 客户端地址 ip:port
+	User      string `json:"user"`       // ACL用户名，断开该用户的所有连接
+}
+
+// Redis暂停客户端请求DTO
+type RedisClientPauseRequest struct {
+	EsConnect     int    `json:"es_connect"`     // 数据源连接ID
+	Database      int    `json:"database"`       // Redis数据库索引，默认为0
+	TimeoutMillis int64  `json:"timeout_millis"` // 暂停时间（毫秒），最大300000
+	Mode          string `json:"mode"`           // 暂停模式 (all, write)，默认write（需要6.2+）；all会同时暂停UNPAUSE，只能等待超时
+}
@@ -0,0 +1,210 @@
+// 后台任务层
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 任务状态
+const (
+	StatusPending     = "pending"     // 等待执行
+	StatusRunning     = "running"     // 执行中
+	StatusSucceeded   = "succeeded"   // 执行成功
+	StatusFailed      = "failed"      // 执行失败
+	StatusCancelled   = "cancelled"   // 已取消
+	StatusInterrupted = "interrupted" // 插件重启导致中断
+)
+
+// Runner 任务执行函数，返回的result会被序列化为JSON保存
+type Runner func(ctx context.Context, job *Job) (result interface{}, err error)
+
+// Job 运行中的后台任务，进度计数器可被Runner并发更新
+type Job struct {
+	ID        string
+	Kind      string
+	EsConnect int
+	Database  int
+	UserId    int
+	Params    json.RawMessage
+	CreatedAt int64
+
+	ResumedFrom string // 断点续跑时的原任务ID
+
+	total     int64
+	processed int64
+	failed    int64
+
+	mu         sync.RWMutex
+	status     string
+	message    string
+	checkpoint string
+	result     json.RawMessage
+	tracked    interface{} // TrackResult登记的累计结果
+	errMsg     string
+	finishedAt int64
+
+	cancel  context.CancelFunc
+	flushMu sync.Mutex // 串行化进度持久化
+}
+
+// BindParams 将任务参数解析到dest
+func (this *Job) BindParams(dest interface{}) error {
+	if len(this.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(this.Params, dest); err != nil {
+		return fmt.Errorf("任务参数格式错误: %w", err)
+	}
+	return nil
+}
+
+// SetTotal 设置任务总量（未知时可不设置）
+func (this *Job) SetTotal(total int64) {
+	atomic.StoreInt64(&this.total, total)
+}
+
+// AddProcessed 增加已处理数量
+func (this *Job) AddProcessed(n int64) {
+	atomic.AddInt64(&this.processed, n)
+}
+
+// AddFailed 增加处理失败数量
+func (this *Job) AddFailed(n int64) {
+	atomic.AddInt64(&this.failed, n)
+}
+
+// SetMessage 设置当前进度说明
+func (this *Job) SetMessage(message string) {
+	this.mu.Lock()
+	this.message = message
+	this.mu.Unlock()
+}
+
+// SetCheckpoint 记录断点（如SCAN游标），用于中断后续跑
+// 登记了TrackResult时同时保存当前的累计结果，保证部分结果与断点一致，续跑时不会重复累计
+func (this *Job) SetCheckpoint(checkpoint string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.checkpoint = checkpoint
+	if this.tracked == nil {
+		return
+	}
+	if b, err := json.Marshal(this.tracked); err == nil {
+		this.result = b
+	}
+}
+
+// TrackResult 登记Runner累计中的结果，每次SetCheckpoint时随断点一起保存
+// 任务失败或取消时保留最后一次保存的部分结果，续跑的任务可通过BindResult取回后继续累计
+// Runner需保证调用SetCheckpoint时没有其他goroutine在修改result
+func (this *Job) TrackResult(result interface{}) {
+	this.mu.Lock()
+	this.tracked = result
+	this.mu.Unlock()
+}
+
+// BindResult 将已保存的部分结果（续跑时为原任务的结果）解析到dest，没有结果时不做任何修改
+func (this *Job) BindResult(dest interface{}) error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	if len(this.result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(this.result, dest); err != nil {
+		return fmt.Errorf("任务结果格式错误: %w", err)
+	}
+	return nil
+}
+
+// Checkpoint 获取当前断点
+func (this *Job) Checkpoint() string {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.checkpoint
+}
+
+// Status 获取当前状态
+func (this *Job) Status() string {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.status
+}
+
+func (this *Job) setStatus(status string) {
+	this.mu.Lock()
+	this.status = status
+	this.mu.Unlock()
+}
+
+// finish 记录任务结束状态与结果，result为空时保留最后一次随断点保存的部分结果
+func (this *Job) finish(status string, result json.RawMessage, errMsg string) {
+	this.mu.Lock()
+	this.status = status
+	if result != nil {
+		this.result = result
+	}
+	this.errMsg = errMsg
+	this.finishedAt = time.Now().Unix()
+	this.mu.Unlock()
+}
+
+// Record 获取任务当前状态的快照
+func (this *Job) Record() *Record {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return &Record{
+		ID:         this.ID,
+		Kind:       this.Kind,
+		Status:     this.status,
+		EsConnect:  this.EsConnect,
+		DbIndex:    this.Database,
+		UserId:     this.UserId,
+		Params:     string(this.Params),
+		Total:      atomic.LoadInt64(&this.total),
+		Processed:  atomic.LoadInt64(&this.processed),
+		Failed:     atomic.LoadInt64(&this.failed),
+		Message:    this.message,
+		Checkpoint: this.checkpoint,
+		Result:     string(this.result),
+		Error:      this.errMsg,
+		CreatedAt:  this.CreatedAt,
+		UpdatedAt:  time.Now().Unix(),
+		FinishedAt: this.finishedAt,
+	}
+}
+
+// Record 任务持久化记录，字段与redis_job表一一对应
+type Record struct {
+	ID         string `json:"id"`
+	Kind       string `json:"kind"`
+	Status     string `json:"status"`
+	EsConnect  int    `json:"es_connect"`
+	DbIndex    int    `json:"db_index"`
+	UserId     int    `json:"user_id"`
+	Params     string `json:"params"`
+	Total      int64  `json:"total"`
+	Processed  int64  `json:"processed"`
+	Failed     int64  `json:"failed"`
+	Message    string `json:"message"`
+	Checkpoint string `json:"checkpoint"`
+	Result     string `json:"result"`
+	Error      string `json:"error"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+	FinishedAt int64  `json:"finished_at"`
+	ResumedBy  string `json:"resumed_by"` // 继续执行本任务的新任务ID，每个任务只能被继续一次
+}
+
+// IsFinished 任务是否已结束
+func (this *Record) IsFinished() bool {
+	switch this.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled, StatusInterrupted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/1340691923/eve-plugin-sdk-go/backend/logger"
+)
+
+const (
+	maxRunningJobs = 4               // 同时运行的任务数上限
+	flushInterval  = 2 * time.Second // 运行中任务进度的持久化间隔
+	listLimit      = 100             // 任务列表最多返回条数
+)
+
+// Manager 后台任务管理器，负责任务的启动、取消、进度持久化
+type Manager struct {
+	mu        sync.RWMutex
+	runners   map[string]Runner
+	writable  map[string]bool
+	resumable map[string]bool
+	jobs      map[string]*Job
+	store     *store
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		runners:   map[string]Runner{},
+		writable:  map[string]bool{},
+		resumable: map[string]bool{},
+		jobs:      map[string]*Job{},
+		store:     &store{},
+	}
+}
+
+// Register 注册任务类型，writable表示任务会修改Redis数据，只能通过对应的写权限接口启动
+func (this *Manager) Register(kind string, writable bool, runner Runner) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.runners[kind] = runner
+	this.writable[kind] = writable
+}
+
+// RegisterResumable 注册可从断点继续的任务类型，Runner需从Job.Checkpoint()开始处理
+func (this *Manager) RegisterResumable(kind string, writable bool, runner Runner) {
+	this.Register(kind, writable, runner)
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.resumable[kind] = true
+}
+
+// IsWritable 任务类型是否会修改Redis数据
+func (this *Manager) IsWritable(kind string) bool {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.writable[kind]
+}
+
+// Start 创建并异步执行一个任务
+func (this *Manager) Start(ctx context.Context, kind string, esConnect, database, userId int, params json.RawMessage) (*Job, error) {
+	return this.start(ctx, kind, esConnect, database, userId, params, nil)
+}
+
+// Resume 从已中断、失败或取消的任务断点继续执行，创建一个沿用原参数与进度的新任务
+func (this *Manager) Resume(ctx context.Context, id string, userId int) (*Job, error) {
+	record, err := this.GetForUser(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !this.isResumable(record.Kind) {
+		return nil, fmt.Errorf("任务类型%s不支持断点续跑", record.Kind)
+	}
+	switch record.Status {
+	case StatusFailed, StatusCancelled, StatusInterrupted:
+	default:
+		return nil, fmt.Errorf("任务状态为%s，无法继续", record.Status)
+	}
+	if record.ResumedBy != "" {
+		return nil, fmt.Errorf("任务%s已被任务%s继续执行，请继续最新的任务", record.ID, record.ResumedBy)
+	}
+
+	return this.start(ctx, record.Kind, record.EsConnect, record.DbIndex, userId, json.RawMessage(record.Params), record)
+}
+
+func (this *Manager) isResumable(kind string) bool {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.resumable[kind]
+}
+
+// start 创建任务并异步执行，from不为空时从该任务的断点与进度继续
+func (this *Manager) start(ctx context.Context, kind string, esConnect, database, userId int, params json.RawMessage, from *Record) (*Job, error) {
+	this.mu.Lock()
+	runner, ok := this.runners[kind]
+	if !ok {
+		this.mu.Unlock()
+		return nil, fmt.Errorf("不支持的任务类型: %s", kind)
+	}
+	running := 0
+	for _, job := range this.jobs {
+		status := job.Status()
+		if status != StatusPending && status != StatusRunning {
+			continue
+		}
+		if from != nil && job.ResumedFrom == from.ID {
+			this.mu.Unlock()
+			return nil, fmt.Errorf("任务%s已在继续执行中: %s", from.ID, job.ID)
+		}
+		running++
+	}
+	if running >= maxRunningJobs {
+		this.mu.Unlock()
+		return nil, fmt.Errorf("当前已有%d个任务在运行，请稍后再试", running)
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:        newJobID(),
+		Kind:      kind,
+		EsConnect: esConnect,
+		Database:  database,
+		UserId:    userId,
+		Params:    params,
+		CreatedAt: time.Now().Unix(),
+		status:    StatusPending,
+		cancel:    cancel,
+	}
+	if from != nil {
+		job.ResumedFrom = from.ID
+		job.checkpoint = from.Checkpoint
+		job.total = from.Total
+		job.processed = from.Processed
+		job.failed = from.Failed
+		if from.Result != "" {
+			job.result = json.RawMessage(from.Result)
+		}
+		job.message = fmt.Sprintf("从任务%s的断点继续", from.ID)
+	}
+	this.jobs[job.ID] = job
+	this.mu.Unlock()
+
+	discard := func() {
+		this.mu.Lock()
+		delete(this.jobs, job.ID)
+		this.mu.Unlock()
+		cancel()
+	}
+
+	// 先占用原任务的断点，避免同一断点被重复继续导致数据被重放
+	if from != nil {
+		if err := this.store.markResumed(ctx, from.ID, job.ID); err != nil {
+			discard()
+			return nil, err
+		}
+	}
+
+	if err := this.store.insert(ctx, job.Record()); err != nil {
+		discard()
+		if from != nil {
+			if clearErr := this.store.clearResumed(ctx, from.ID, job.ID); clearErr != nil {
+				logger.DefaultLogger.Error("撤销任务继续标记失败", "id:", from.ID, "error:", clearErr)
+			}
+		}
+		return nil, fmt.Errorf("保存任务记录失败: %w", err)
+	}
+
+	go this.run(jobCtx, job, runner)
+
+	return job, nil
+}
+
+// run 执行任务并定期持久化进度
+func (this *Manager) run(ctx context.Context, job *Job, runner Runner) {
+	defer job.cancel()
+
+	job.setStatus(StatusRunning)
+	logger.DefaultLogger.Debug("后台任务开始", "id:", job.ID, "kind:", job.Kind)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				this.flush(job)
+			}
+		}
+	}()
+
+	result, err := this.safeRun(ctx, job, runner)
+	// 等待定时持久化退出，避免进行中的一次写入把running状态覆盖到最终状态之后
+	close(done)
+	<-stopped
+
+	switch {
+	case err == nil:
+		b, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			job.finish(StatusFailed, nil, fmt.Sprintf("序列化任务结果失败: %v", marshalErr))
+		} else {
+			job.finish(StatusSucceeded, b, "")
+		}
+	case ctx.Err() != nil:
+		job.finish(StatusCancelled, nil, "任务已取消")
+	default:
+		job.finish(StatusFailed, nil, err.Error())
+	}
+
+	// 结束状态落库成功后即可从内存移除，之后的查询走数据库
+	if err := this.flush(job); err == nil {
+		this.mu.Lock()
+		delete(this.jobs, job.ID)
+		this.mu.Unlock()
+	}
+
+	logger.DefaultLogger.Debug("后台任务结束", "id:", job.ID, "kind:", job.Kind, "status:", job.Status())
+}
+
+// safeRun 执行Runner并将panic转换为错误，避免拖垮插件进程
+func (this *Manager) safeRun(ctx context.Context, job *Job, runner Runner) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.DefaultLogger.Error("后台任务panic", "id:", job.ID, "kind:", job.Kind, "panic:", r)
+			err = fmt.Errorf("任务执行异常: %v", r)
+		}
+	}()
+	return runner(ctx, job)
+}
+
+// flush 持久化任务当前状态，同一任务的写入串行执行，保证后取的快照后写入
+func (this *Manager) flush(job *Job) error {
+	job.flushMu.Lock()
+	defer job.flushMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := this.store.update(ctx, job.Record())
+	if err != nil {
+		logger.DefaultLogger.Error("保存任务进度失败", "id:", job.ID, "error:", err)
+	}
+	return err
+}
+
+// Cancel 取消指定用户运行中的任务
+func (this *Manager) Cancel(id string, userId int) error {
+	this.mu.RLock()
+	job, ok := this.jobs[id]
+	this.mu.RUnlock()
+	if !ok || job.UserId != userId {
+		return fmt.Errorf("任务不存在或已不在运行: %s", id)
+	}
+	if status := job.Status(); status != StatusPending && status != StatusRunning {
+		return fmt.Errorf("任务状态为%s，无法取消", status)
+	}
+	job.cancel()
+	return nil
+}
+
+// Get 获取任务记录，运行中的任务返回内存中的实时进度
+func (this *Manager) Get(ctx context.Context, id string) (*Record, error) {
+	this.mu.RLock()
+	job, ok := this.jobs[id]
+	this.mu.RUnlock()
+	if ok {
+		return job.Record(), nil
+	}
+
+	record, err := this.store.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 不在内存中却仍是运行状态，说明插件在任务执行期间重启过
+	if !record.IsFinished() {
+		record.Status = StatusInterrupted
+	}
+	return record, nil
+}
+
+// GetForUser 获取指定用户的任务记录，其他用户的任务按不存在处理
+func (this *Manager) GetForUser(ctx context.Context, id string, userId int) (*Record, error) {
+	record, err := this.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.UserId != userId {
+		return nil, fmt.Errorf("任务不存在: %s", id)
+	}
+	return record, nil
+}
+
+// List 获取指定用户最近的任务列表
+func (this *Manager) List(ctx context.Context, kind string, userId int) ([]*Record, error) {
+	records, err := this.store.list(ctx, kind, userId, listLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	for i, record := range records {
+		if job, ok := this.jobs[record.ID]; ok {
+			live := job.Record()
+			live.Result = ""
+			records[i] = live
+		} else if !record.IsFinished() {
+			record.Status = StatusInterrupted
+		}
+	}
+	return records, nil
+}
+
+func newJobID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+)
+
+const recordColumns = "id, kind, status, es_connect, db_index, user_id, params, total, processed, failed, " +
+	"message, checkpoint, result, error, created_at, updated_at, finished_at, resumed_by"
+
+// store 任务记录的持久化，使用插件自身的数据库（表结构见migrate.V0_0_2）
+type store struct {
+}
+
+func (this *store) insert(ctx context.Context, record *Record) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"INSERT INTO redis_job ("+recordColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		record.ID, record.Kind, record.Status, record.EsConnect, record.DbIndex, record.UserId, record.Params,
+		record.Total, record.Processed, record.Failed, record.Message, record.Checkpoint, record.Result,
+		record.Error, record.CreatedAt, record.UpdatedAt, record.FinishedAt, record.ResumedBy,
+	)
+	return err
+}
+
+// markResumed 将任务标记为已被resumedBy继续执行，已被标记过时返回错误，保证同一个断点只会被继续一次
+func (this *store) markResumed(ctx context.Context, id, resumedBy string) error {
+	rows, err := ev_api.GetEvApi().StoreExec(ctx,
+		"UPDATE redis_job SET resumed_by = ? WHERE id = ? AND (resumed_by = '' OR resumed_by IS NULL)", resumedBy, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("任务%s已被继续执行过，请继续最新的任务", id)
+	}
+	return nil
+}
+
+// clearResumed 撤销markResumed的标记，用于新任务创建失败时
+func (this *store) clearResumed(ctx context.Context, id, resumedBy string) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"UPDATE redis_job SET resumed_by = '' WHERE id = ? AND resumed_by = ?", id, resumedBy)
+	return err
+}
+
+func (this *store) update(ctx context.Context, record *Record) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"UPDATE redis_job SET status = ?, total = ?, processed = ?, failed = ?, message = ?, checkpoint = ?, "+
+			"result = ?, error = ?, updated_at = ?, finished_at = ? WHERE id = ?",
+		record.Status, record.Total, record.Processed, record.Failed, record.Message, record.Checkpoint,
+		record.Result, record.Error, record.UpdatedAt, record.FinishedAt, record.ID,
+	)
+	return err
+}
+
+func (this *store) get(ctx context.Context, id string) (*Record, error) {
+	var records []*Record
+	err := ev_api.GetEvApi().StoreSelect(ctx, &records, "SELECT "+recordColumns+" FROM redis_job WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("任务不存在: %s", id)
+	}
+	return records[0], nil
+}
+
+func (this *store) list(ctx context.Context, kind string, userId int, limit int) ([]*Record, error) {
+	var records []*Record
+	var err error
+	// 列表不返回result，避免大结果拖慢查询
+	columns := "id, kind, status, es_connect, db_index, user_id, params, total, processed, failed, " +
+		"message, checkpoint, error, created_at, updated_at, finished_at, resumed_by"
+	if kind == "" {
+		err = ev_api.GetEvApi().StoreSelect(ctx, &records,
+			"SELECT "+columns+" FROM redis_job WHERE user_id = ? ORDER BY created_at DESC LIMIT ?", userId, limit)
+	} else {
+		err = ev_api.GetEvApi().StoreSelect(ctx, &records,
+			"SELECT "+columns+" FROM redis_job WHERE user_id = ? AND kind = ? ORDER BY created_at DESC LIMIT ?", userId, kind, limit)
+	}
+	return records, err
+}
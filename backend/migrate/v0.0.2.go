@@ -0,0 +1,73 @@
+package migrate
+
+import (
+	"github.com/1340691923/eve-plugin-sdk-go/build"
+)
+
+// V0_0_2 后台任务记录表
+func V0_0_2() *build.Migration {
+	return &build.Migration{
+		ID: "0.0.2",
+		SqliteMigrateSqls: []*build.ExecSql{
+			{
+				Sql: `create table redis_job
+(
+    id          TEXT    not null primary key,
+    kind        TEXT    default '',
+    status      TEXT    default '',
+    es_connect  INTEGER default 0,
+    db_index    INTEGER default 0,
+    user_id     INTEGER default 0,
+    params      TEXT    default '',
+    total       INTEGER default 0,
+    processed   INTEGER default 0,
+    failed      INTEGER default 0,
+    message     TEXT    default '',
+    checkpoint  TEXT    default '',
+    result      TEXT    default '',
+    error       TEXT    default '',
+    created_at  INTEGER default 0,
+    updated_at  INTEGER default 0,
+    finished_at INTEGER default 0,
+    resumed_by  TEXT    default ''
+);
+`,
+			},
+			{
+				Sql: `create index idx_redis_job_created_at on redis_job (created_at);`,
+			},
+		},
+		MysqlMigrateSqls: []*build.ExecSql{
+			{
+				Sql: "CREATE TABLE redis_job " +
+					"(    id      varchar(64)   NOT NULL," +
+					"   `kind`  varchar(64)   DEFAULT ''," +
+					"   `status`  varchar(32)   DEFAULT ''," +
+					"   `es_connect`  int(11)   DEFAULT 0," +
+					"   `db_index`  int(11)   DEFAULT 0," +
+					"   `user_id`  int(11)   DEFAULT 0," +
+					"   `params`  longtext," +
+					"   `total`  bigint(20)   DEFAULT 0," +
+					"   `processed`  bigint(20)   DEFAULT 0," +
+					"   `failed`  bigint(20)   DEFAULT 0," +
+					"   `message`  text," +
+					"   `checkpoint`  longtext," +
+					"   `result`  longtext," +
+					"   `error`  text," +
+					"   `created_at`  bigint(20)   DEFAULT 0," +
+					"   `updated_at`  bigint(20)   DEFAULT 0," +
+					"   `finished_at`  bigint(20)   DEFAULT 0," +
+					"   `resumed_by`  varchar(64)   DEFAULT ''," +
+					"    PRIMARY KEY (id) USING BTREE," +
+					"    KEY idx_redis_job_created_at (created_at)" +
+					") ENGINE = InnoDB ;",
+			},
+		},
+		SqliteRollback: []*build.ExecSql{
+			{Sql: "drop table if exists redis_job;"},
+		},
+		MysqlRollback: []*build.ExecSql{
+			{Sql: "DROP TABLE IF EXISTS redis_job;"},
+		},
+	}
+}
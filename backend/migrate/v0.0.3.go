@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"github.com/1340691923/eve-plugin-sdk-go/build"
+)
+
+// V0_0_3 Key快照表，快照明细保存DUMP序列化值（十六进制）与剩余TTL
+func V0_0_3() *build.Migration {
+	return &build.Migration{
+		ID: "0.0.3",
+		SqliteMigrateSqls: []*build.ExecSql{
+			{
+				Sql: `create table redis_snapshot
+(
+    id          TEXT    not null primary key,
+    name        TEXT    default '',
+    es_connect  INTEGER default 0,
+    db_index    INTEGER default 0,
+    user_id     INTEGER default 0,
+    pattern     TEXT    default '',
+    job_id      TEXT    default '',
+    status      TEXT    default '',
+    key_count   INTEGER default 0,
+    total_bytes INTEGER default 0,
+    message     TEXT    default '',
+    created_at  INTEGER default 0,
+    finished_at INTEGER default 0
+);
+`,
+			},
+			{
+				Sql: `create index idx_redis_snapshot_created_at on redis_snapshot (created_at);`,
+			},
+			{
+				Sql: `create table redis_snapshot_key
+(
+    id          INTEGER not null primary key autoincrement,
+    snapshot_id TEXT    default '',
+    key_name    TEXT    default '',
+    pttl        INTEGER default 0,
+    size        INTEGER default 0,
+    payload     TEXT    default ''
+);
+`,
+			},
+			{
+				Sql: `create index idx_redis_snapshot_key_snapshot_id on redis_snapshot_key (snapshot_id);`,
+			},
+		},
+		MysqlMigrateSqls: []*build.ExecSql{
+			{
+				Sql: "CREATE TABLE redis_snapshot " +
+					"(    id      varchar(64)   NOT NULL," +
+					"   `name`  varchar(255)   DEFAULT ''," +
+					"   `es_connect`  int(11)   DEFAULT 0," +
+					"   `db_index`  int(11)   DEFAULT 0," +
+					"   `user_id`  int(11)   DEFAULT 0," +
+					"   `pattern`  varchar(1024)   DEFAULT ''," +
+					"   `job_id`  varchar(64)   DEFAULT ''," +
+					"   `status`  varchar(32)   DEFAULT ''," +
+					"   `key_count`  bigint(20)   DEFAULT 0," +
+					"   `total_bytes`  bigint(20)   DEFAULT 0," +
+					"   `message`  text," +
+					"   `created_at`  bigint(20)   DEFAULT 0," +
+					"   `finished_at`  bigint(20)   DEFAULT 0," +
+					"    PRIMARY KEY (id) USING BTREE," +
+					"    KEY idx_redis_snapshot_created_at (created_at)" +
+					") ENGINE = InnoDB ;",
+			},
+			{
+				Sql: "CREATE TABLE redis_snapshot_key " +
+					"(    id      bigint(20) NOT NULL AUTO_INCREMENT," +
+					"   `snapshot_id`  varchar(64)   DEFAULT ''," +
+					"   `key_name`  text," +
+					"   `pttl`  bigint(20)   DEFAULT 0," +
+					"   `size`  bigint(20)   DEFAULT 0," +
+					"   `payload`  longtext," +
+					"    PRIMARY KEY (id) USING BTREE," +
+					"    KEY idx_redis_snapshot_key_snapshot_id (snapshot_id)" +
+					") ENGINE = InnoDB ;",
+			},
+		},
+		SqliteRollback: []*build.ExecSql{
+			{Sql: "drop table if exists redis_snapshot_key;"},
+			{Sql: "drop table if exists redis_snapshot;"},
+		},
+		MysqlRollback: []*build.ExecSql{
+			{Sql: "DROP TABLE IF EXISTS redis_snapshot_key;"},
+			{Sql: "DROP TABLE IF EXISTS redis_snapshot;"},
+		},
+	}
+}
@@ -1,6 +1,13 @@
 // 自定义异常层
 package my_error
 
+// 业务错误码
+const (
+	KeyNotFound        = 40401 // Key不存在
+	KeyVersionConflict = 40901 // Key在编辑期间已被他人修改
+	KeyAlreadyExists   = 40902 // 目标Key已存在
+)
+
 // 自定义异常结构体 实现Error方法
 type MyError struct {
 	code int
@@ -0,0 +1,233 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ziplistCount 统计ziplist中的元素数量，头部计数达到65535时需要遍历
+func ziplistCount(blob []byte) (int64, error) {
+	if len(blob) < 11 {
+		return 0, fmt.Errorf("ziplist长度错误")
+	}
+	if count := binary.LittleEndian.Uint16(blob[8:10]); count < 0xFFFF {
+		return int64(count), nil
+	}
+
+	var count int64
+	for pos := 10; ; count++ {
+		if pos >= len(blob) {
+			return 0, fmt.Errorf("ziplist缺少结束标记")
+		}
+		if blob[pos] == 0xFF {
+			return count, nil
+		}
+		// 前一个元素的长度：1字节，或0xFE加4字节
+		if blob[pos] == 0xFE {
+			pos += 5
+		} else {
+			pos++
+		}
+		if pos >= len(blob) {
+			return 0, fmt.Errorf("ziplist元素不完整")
+		}
+
+		enc := blob[pos]
+		switch {
+		case enc>>6 == 0:
+			pos += 1 + int(enc&0x3F)
+		case enc>>6 == 1:
+			if pos+1 >= len(blob) {
+				return 0, fmt.Errorf("ziplist元素不完整")
+			}
+			pos += 2 + (int(enc&0x3F)<<8 | int(blob[pos+1]))
+		case enc == 0x80:
+			if pos+4 >= len(blob) {
+				return 0, fmt.Errorf("ziplist元素不完整")
+			}
+			pos += 5 + int(binary.BigEndian.Uint32(blob[pos+1:pos+5]))
+		case enc == 0xC0:
+			pos += 3
+		case enc == 0xD0:
+			pos += 5
+		case enc == 0xE0:
+			pos += 9
+		case enc == 0xF0:
+			pos += 4
+		case enc == 0xFE:
+			pos += 2
+		case enc >= 0xF1 && enc <= 0xFD:
+			pos++ // 0-12的立即数
+		default:
+			return 0, fmt.Errorf("未知的ziplist编码: 0x%X", enc)
+		}
+	}
+}
+
+// listpackCount 统计listpack中的元素数量，头部计数达到65535时需要遍历
+func listpackCount(blob []byte) (int64, error) {
+	if len(blob) < 7 {
+		return 0, fmt.Errorf("listpack长度错误")
+	}
+	if count := binary.LittleEndian.Uint16(blob[4:6]); count < 0xFFFF {
+		return int64(count), nil
+	}
+
+	var count int64
+	for pos := 6; ; count++ {
+		if pos >= len(blob) {
+			return 0, fmt.Errorf("listpack缺少结束标记")
+		}
+		enc := blob[pos]
+		if enc == 0xFF {
+			return count, nil
+		}
+
+		var size int // 编码与数据的长度，不含backlen
+		switch {
+		case enc>>7 == 0:
+			size = 1
+		case enc>>6 == 2:
+			size = 1 + int(enc&0x3F)
+		case enc>>5 == 6:
+			size = 2
+		case enc>>4 == 14:
+			if pos+1 >= len(blob) {
+				return 0, fmt.Errorf("listpack元素不完整")
+			}
+			size = 2 + (int(enc&0x0F)<<8 | int(blob[pos+1]))
+		case enc == 0xF0:
+			if pos+4 >= len(blob) {
+				return 0, fmt.Errorf("listpack元素不完整")
+			}
+			size = 5 + int(binary.LittleEndian.Uint32(blob[pos+1:pos+5]))
+		case enc == 0xF1:
+			size = 3
+		case enc == 0xF2:
+			size = 4
+		case enc == 0xF3:
+			size = 5
+		case enc == 0xF4:
+			size = 9
+		default:
+			return 0, fmt.Errorf("未知的listpack编码: 0x%X", enc)
+		}
+		pos += size + listpackBacklenSize(size)
+	}
+}
+
+// listpackBacklenSize 元素末尾记录元素长度所需的字节数，每字节保存7位
+// 边界与Redis的lpEncodeBacklenBytes保持一致（除第一档外均为开区间），不能按2的幂计算
+func listpackBacklenSize(size int) int {
+	switch {
+	case size <= 127:
+		return 1
+	case size < 16383:
+		return 2
+	case size < 2097151:
+		return 3
+	case size < 268435455:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// intsetCount 读取intset头部的元素数量
+func intsetCount(blob []byte) (int64, error) {
+	if len(blob) < 8 {
+		return 0, fmt.Errorf("intset长度错误")
+	}
+	return int64(binary.LittleEndian.Uint32(blob[4:8])), nil
+}
+
+// zipmapCount 统计zipmap中的字段数量，头部计数达到254时需要遍历
+func zipmapCount(blob []byte) (int64, error) {
+	if len(blob) < 2 {
+		return 0, fmt.Errorf("zipmap长度错误")
+	}
+	if blob[0] < 254 {
+		return int64(blob[0]), nil
+	}
+
+	readLen := func(pos int) (int, int, error) {
+		if pos >= len(blob) {
+			return 0, 0, fmt.Errorf("zipmap元素不完整")
+		}
+		if blob[pos] < 254 {
+			return int(blob[pos]), pos + 1, nil
+		}
+		if pos+4 >= len(blob) {
+			return 0, 0, fmt.Errorf("zipmap元素不完整")
+		}
+		return int(binary.LittleEndian.Uint32(blob[pos+1 : pos+5])), pos + 5, nil
+	}
+
+	var count int64
+	for pos := 1; ; count++ {
+		if pos >= len(blob) {
+			return 0, fmt.Errorf("zipmap缺少结束标记")
+		}
+		if blob[pos] == 0xFF {
+			return count, nil
+		}
+		keyLen, next, err := readLen(pos)
+		if err != nil {
+			return 0, err
+		}
+		valueLen, next, err := readLen(next + keyLen)
+		if err != nil {
+			return 0, err
+		}
+		if next >= len(blob) {
+			return 0, fmt.Errorf("zipmap元素不完整")
+		}
+		free := int(blob[next])
+		pos = next + 1 + valueLen + free
+	}
+}
+
+// lzfDecompress 解压LZF压缩的字符串
+func lzfDecompress(in []byte, outLen int) ([]byte, error) {
+	out := make([]byte, 0, outLen)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+
+		if ctrl < 32 {
+			// 字面量
+			n := ctrl + 1
+			if i+n > len(in) {
+				return nil, fmt.Errorf("LZF数据不完整")
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		// 回溯引用
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, fmt.Errorf("LZF数据不完整")
+			}
+			length += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, fmt.Errorf("LZF数据不完整")
+		}
+		ref := len(out) - (ctrl&0x1F)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, fmt.Errorf("LZF回溯引用越界")
+		}
+		for k := 0; k < length+2; k++ {
+			out = append(out, out[ref+k])
+		}
+	}
+	if len(out) != outLen {
+		return nil, fmt.Errorf("LZF解压后长度为%d，期望%d", len(out), outLen)
+	}
+	return out, nil
+}
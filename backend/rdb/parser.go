@@ -0,0 +1,738 @@
+// RDB文件离线解析，只统计每个Key的类型、编码、序列化大小、元素数量与过期时间，不还原具体的值
+package rdb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// 特殊操作码
+const (
+	opSlotInfo      = 0xF4 // 7.4+ 集群slot信息
+	opFunction2     = 0xF5 // 7.0+ 函数库
+	opFunctionPreGA = 0xF6 // 7.0 RC版本的函数库，不支持
+	opModuleAux     = 0xF7 // 模块辅助数据
+	opIdle          = 0xF8 // LRU空闲时间
+	opFreq          = 0xF9 // LFU访问频率
+	opAux           = 0xFA // 辅助字段
+	opResizeDB      = 0xFB // 库大小提示
+	opExpireTimeMs  = 0xFC // 毫秒过期时间
+	opExpireTime    = 0xFD // 秒过期时间
+	opSelectDB      = 0xFE // 切换库
+	opEOF           = 0xFF // 文件结束
+)
+
+const maxSupportedVersion = 12 // 支持的最高RDB版本（Redis 7.4）
+
+// 模块数据中的操作码
+const (
+	moduleOpcodeEOF    = 0
+	moduleOpcodeSInt   = 1
+	moduleOpcodeUInt   = 2
+	moduleOpcodeFloat  = 3
+	moduleOpcodeDouble = 4
+	moduleOpcodeString = 5
+)
+
+// 值类型
+const (
+	typeString            = 0
+	typeList              = 1
+	typeSet               = 2
+	typeZSet              = 3
+	typeHash              = 4
+	typeZSet2             = 5
+	typeModulePreGA       = 6
+	typeModule2           = 7
+	typeHashZipmap        = 9
+	typeListZiplist       = 10
+	typeSetIntset         = 11
+	typeZSetZiplist       = 12
+	typeHashZiplist       = 13
+	typeListQuicklist     = 14
+	typeStreamListpacks   = 15
+	typeHashListpack      = 16
+	typeZSetListpack      = 17
+	typeListQuicklist2    = 18
+	typeStreamListpacks2  = 19
+	typeSetListpack       = 20
+	typeStreamListpacks3  = 21
+	typeHashMetadataPreGA = 22
+	typeHashListpackExPre = 23
+	typeHashMetadata      = 24
+	typeHashListpackEx    = 25
+)
+
+// 长度编码中的特殊字符串编码
+const (
+	encInt8  = 0
+	encInt16 = 1
+	encInt32 = 2
+	encLZF   = 3
+)
+
+// Entry 解析出的单个Key
+type Entry struct {
+	Database int    // 所在库
+	Key      string // Key名称
+	Type     string // 数据类型 (string, list, set, zset, hash, stream, module)
+	Encoding string // RDB中的存储编码，如int、raw、listpack、quicklist、hashtable、skiplist
+	Size     int64  // 在RDB中序列化后的字节数（含类型与Key名）
+	Elements int64  // 元素数量，string为字节长度
+	ExpireAt int64  // 过期时间（unix毫秒），0表示不过期
+}
+
+// Header RDB文件信息
+type Header struct {
+	Version int               // RDB版本
+	Aux     map[string]string // 辅助字段，如redis-ver、ctime、used-mem
+}
+
+// Parser RDB解析器，按顺序读取，不需要随机访问，可直接解析上传的流
+type Parser struct {
+	r      *bufio.Reader
+	offset int64
+	header *Header
+}
+
+func NewParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReaderSize(r, 1024*1024)}
+}
+
+// Offset 已读取的字节数
+func (this *Parser) Offset() int64 {
+	return this.offset
+}
+
+// Header 已解析的文件信息，辅助字段位于所有Key之前，handler中即可读取
+func (this *Parser) Header() *Header {
+	return this.header
+}
+
+// Parse 逐个Key解析，handler返回错误时终止解析并返回该错误
+func (this *Parser) Parse(handler func(entry *Entry) error) (*Header, error) {
+	magic, err := this.readBytes(9)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件头失败: %w", err)
+	}
+	if string(magic[:5]) != "REDIS" {
+		return nil, fmt.Errorf("不是RDB文件")
+	}
+	version, err := strconv.Atoi(string(magic[5:]))
+	if err != nil {
+		return nil, fmt.Errorf("RDB版本号格式错误: %q", magic[5:])
+	}
+	if version < 1 || version > maxSupportedVersion {
+		return nil, fmt.Errorf("不支持的RDB版本: %d", version)
+	}
+
+	header := &Header{Version: version, Aux: map[string]string{}}
+	this.header = header
+	database := 0
+	var expireAt int64
+
+	for {
+		start := this.offset
+		opcode, err := this.readByte()
+		if err != nil {
+			return header, fmt.Errorf("文件在偏移%d处意外结束: %w", start, err)
+		}
+
+		switch opcode {
+		case opEOF:
+			// 之后是8字节校验和（版本5起），不做校验
+			return header, nil
+		case opSelectDB:
+			db, err := this.readLength()
+			if err != nil {
+				return header, err
+			}
+			database = int(db)
+		case opResizeDB:
+			if _, err := this.readLength(); err != nil {
+				return header, err
+			}
+			if _, err := this.readLength(); err != nil {
+				return header, err
+			}
+		case opAux:
+			key, err := this.readString()
+			if err != nil {
+				return header, err
+			}
+			value, err := this.readString()
+			if err != nil {
+				return header, err
+			}
+			header.Aux[string(key)] = string(value)
+		case opExpireTime:
+			buf, err := this.readBytes(4)
+			if err != nil {
+				return header, err
+			}
+			expireAt = int64(binary.LittleEndian.Uint32(buf)) * 1000
+		case opExpireTimeMs:
+			if expireAt, err = this.readMillisecondTime(); err != nil {
+				return header, err
+			}
+		case opFreq:
+			if _, err := this.readByte(); err != nil {
+				return header, err
+			}
+		case opIdle:
+			if _, err := this.readLength(); err != nil {
+				return header, err
+			}
+		case opSlotInfo:
+			for i := 0; i < 3; i++ {
+				if _, err := this.readLength(); err != nil {
+					return header, err
+				}
+			}
+		case opModuleAux:
+			// 模块ID、when_opcode、when，之后与模块值的格式相同
+			for i := 0; i < 3; i++ {
+				if _, err := this.readLength(); err != nil {
+					return header, err
+				}
+			}
+			if err := this.skipModuleValue(); err != nil {
+				return header, err
+			}
+		case opFunction2:
+			if _, err := this.skipString(); err != nil {
+				return header, err
+			}
+		case opFunctionPreGA:
+			return header, fmt.Errorf("不支持Redis 7.0 RC版本的函数库格式")
+		default:
+			key, err := this.readString()
+			if err != nil {
+				return header, fmt.Errorf("读取偏移%d处的Key失败: %w", start, err)
+			}
+			entry := &Entry{Database: database, Key: string(key), ExpireAt: expireAt}
+			if err := this.readValue(opcode, entry); err != nil {
+				return header, fmt.Errorf("解析Key %q 失败: %w", entry.Key, err)
+			}
+			entry.Size = this.offset - start
+			expireAt = 0
+
+			if err := handler(entry); err != nil {
+				return header, err
+			}
+		}
+	}
+}
+
+// readValue 按值类型读取，只统计元素数量
+func (this *Parser) readValue(valueType byte, entry *Entry) error {
+	var err error
+	switch valueType {
+	case typeString:
+		entry.Type, entry.Encoding = "string", "raw"
+		var length int64
+		var isInt bool
+		length, isInt, err = this.skipStringInfo()
+		if isInt {
+			entry.Encoding = "int"
+		}
+		entry.Elements = length
+	case typeList, typeSet:
+		entry.Type, entry.Encoding = "list", "linkedlist"
+		if valueType == typeSet {
+			entry.Type, entry.Encoding = "set", "hashtable"
+		}
+		entry.Elements, err = this.skipStrings(1)
+	case typeZSet, typeZSet2:
+		entry.Type, entry.Encoding = "zset", "skiplist"
+		entry.Elements, err = this.skipZSet(valueType == typeZSet2)
+	case typeHash:
+		entry.Type, entry.Encoding = "hash", "hashtable"
+		entry.Elements, err = this.skipStrings(2)
+	case typeHashZipmap:
+		entry.Type, entry.Encoding = "hash", "zipmap"
+		entry.Elements, err = this.readBlobCount(zipmapCount)
+	case typeListZiplist:
+		entry.Type, entry.Encoding = "list", "ziplist"
+		entry.Elements, err = this.readBlobCount(ziplistCount)
+	case typeSetIntset:
+		entry.Type, entry.Encoding = "set", "intset"
+		entry.Elements, err = this.readBlobCount(intsetCount)
+	case typeZSetZiplist, typeHashZiplist:
+		entry.Type, entry.Encoding = "zset", "ziplist"
+		if valueType == typeHashZiplist {
+			entry.Type = "hash"
+		}
+		entry.Elements, err = this.readBlobCount(ziplistCount)
+		entry.Elements /= 2
+	case typeHashListpack, typeZSetListpack, typeSetListpack:
+		entry.Type, entry.Encoding = "hash", "listpack"
+		if valueType == typeZSetListpack {
+			entry.Type = "zset"
+		} else if valueType == typeSetListpack {
+			entry.Type = "set"
+		}
+		entry.Elements, err = this.readBlobCount(listpackCount)
+		if valueType != typeSetListpack {
+			entry.Elements /= 2
+		}
+	case typeListQuicklist, typeListQuicklist2:
+		entry.Type, entry.Encoding = "list", "quicklist"
+		entry.Elements, err = this.skipQuicklist(valueType == typeListQuicklist2)
+	case typeHashMetadata, typeHashMetadataPreGA:
+		entry.Type, entry.Encoding = "hash", "hashtable"
+		entry.Elements, err = this.skipHashMetadata(valueType == typeHashMetadata)
+	case typeHashListpackEx, typeHashListpackExPre:
+		entry.Type, entry.Encoding = "hash", "listpackex"
+		if valueType == typeHashListpackEx {
+			// 最小字段过期时间
+			if _, err = this.readMillisecondTime(); err != nil {
+				return err
+			}
+		}
+		// 每个字段占field、value、ttl三个元素
+		entry.Elements, err = this.readBlobCount(listpackCount)
+		entry.Elements /= 3
+	case typeStreamListpacks, typeStreamListpacks2, typeStreamListpacks3:
+		entry.Type, entry.Encoding = "stream", "stream"
+		entry.Elements, err = this.skipStream(valueType)
+	case typeModule2:
+		entry.Type = "module"
+		var moduleId uint64
+		if moduleId, err = this.readLength(); err != nil {
+			return err
+		}
+		entry.Encoding = moduleName(moduleId)
+		err = this.skipModuleValue()
+	case typeModulePreGA:
+		return fmt.Errorf("不支持旧版本的模块数据格式")
+	default:
+		return fmt.Errorf("未知的值类型: %d", valueType)
+	}
+	return err
+}
+
+// skipStrings 跳过长度前缀的字符串列表，每个元素包含n个字符串，返回元素数量
+func (this *Parser) skipStrings(n int) (int64, error) {
+	length, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < length*uint64(n); i++ {
+		if _, err := this.skipString(); err != nil {
+			return 0, err
+		}
+	}
+	return int64(length), nil
+}
+
+func (this *Parser) skipZSet(binaryScore bool) (int64, error) {
+	length, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < length; i++ {
+		if _, err := this.skipString(); err != nil {
+			return 0, err
+		}
+		if binaryScore {
+			err = this.skip(8)
+		} else {
+			// 1字节长度，253/254/255分别表示nan/+inf/-inf
+			var n byte
+			if n, err = this.readByte(); err == nil && n < 253 {
+				err = this.skip(int64(n))
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int64(length), nil
+}
+
+// skipQuicklist 跳过quicklist，v2中每个节点带有容器类型（1为单个大元素，2为listpack）
+func (this *Parser) skipQuicklist(v2 bool) (int64, error) {
+	nodes, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for i := uint64(0); i < nodes; i++ {
+		if v2 {
+			container, err := this.readLength()
+			if err != nil {
+				return 0, err
+			}
+			if container == 1 {
+				if _, err := this.skipString(); err != nil {
+					return 0, err
+				}
+				count++
+				continue
+			}
+			n, err := this.readBlobCount(listpackCount)
+			if err != nil {
+				return 0, err
+			}
+			count += n
+			continue
+		}
+		n, err := this.readBlobCount(ziplistCount)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// skipHashMetadata 跳过带字段过期时间的hash（7.4+），正式版本的TTL是相对最小过期时间的长度编码
+func (this *Parser) skipHashMetadata(ga bool) (int64, error) {
+	if ga {
+		if _, err := this.readMillisecondTime(); err != nil {
+			return 0, err
+		}
+	}
+	length, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < length; i++ {
+		if ga {
+			_, err = this.readLength()
+		} else {
+			_, err = this.readMillisecondTime()
+		}
+		if err != nil {
+			return 0, err
+		}
+		for k := 0; k < 2; k++ {
+			if _, err := this.skipString(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return int64(length), nil
+}
+
+// skipStream 跳过stream，返回消息数量
+func (this *Parser) skipStream(valueType byte) (int64, error) {
+	listpacks, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < listpacks; i++ {
+		// 节点的起始ID与listpack
+		for k := 0; k < 2; k++ {
+			if _, err := this.skipString(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	length, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	// last_id，v2起还有first_id、max_deleted_entry_id、entries_added
+	lengths := 2
+	if valueType >= typeStreamListpacks2 {
+		lengths += 5
+	}
+	if err := this.skipLengths(lengths); err != nil {
+		return 0, err
+	}
+
+	groups, err := this.readLength()
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < groups; i++ {
+		if _, err := this.skipString(); err != nil {
+			return 0, err
+		}
+		// last_id，v2起还有entries_read
+		lengths := 2
+		if valueType >= typeStreamListpacks2 {
+			lengths++
+		}
+		if err := this.skipLengths(lengths); err != nil {
+			return 0, err
+		}
+
+		// 消费组PEL：16字节ID、8字节投递时间、投递次数
+		pending, err := this.readLength()
+		if err != nil {
+			return 0, err
+		}
+		for k := uint64(0); k < pending; k++ {
+			if err := this.skip(16 + 8); err != nil {
+				return 0, err
+			}
+			if _, err := this.readLength(); err != nil {
+				return 0, err
+			}
+		}
+
+		consumers, err := this.readLength()
+		if err != nil {
+			return 0, err
+		}
+		for k := uint64(0); k < consumers; k++ {
+			if _, err := this.skipString(); err != nil {
+				return 0, err
+			}
+			// seen_time，v3起还有active_time
+			times := int64(8)
+			if valueType >= typeStreamListpacks3 {
+				times += 8
+			}
+			if err := this.skip(times); err != nil {
+				return 0, err
+			}
+			pel, err := this.readLength()
+			if err != nil {
+				return 0, err
+			}
+			if err := this.skip(int64(pel) * 16); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return int64(length), nil
+}
+
+// skipModuleValue 跳过模块序列化的数据，直到模块EOF操作码
+func (this *Parser) skipModuleValue() error {
+	for {
+		opcode, err := this.readLength()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case moduleOpcodeEOF:
+			return nil
+		case moduleOpcodeSInt, moduleOpcodeUInt:
+			_, err = this.readLength()
+		case moduleOpcodeFloat:
+			err = this.skip(4)
+		case moduleOpcodeDouble:
+			err = this.skip(8)
+		case moduleOpcodeString:
+			_, err = this.skipString()
+		default:
+			return fmt.Errorf("未知的模块数据操作码: %d", opcode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (this *Parser) skipLengths(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := this.readLength(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBlobCount 读取ziplist、listpack等以字符串形式保存的结构，并用count统计其中的元素数量
+func (this *Parser) readBlobCount(count func(blob []byte) (int64, error)) (int64, error) {
+	blob, err := this.readString()
+	if err != nil {
+		return 0, err
+	}
+	return count(blob)
+}
+
+// readLengthWithEncoding 读取长度编码，encoded为true时返回值表示特殊字符串编码
+func (this *Parser) readLengthWithEncoding() (uint64, bool, error) {
+	b, err := this.readByte()
+	if err != nil {
+		return 0, false, err
+	}
+	switch b >> 6 {
+	case 0:
+		return uint64(b & 0x3F), false, nil
+	case 1:
+		next, err := this.readByte()
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b&0x3F)<<8 | uint64(next), false, nil
+	case 2:
+		switch b {
+		case 0x80:
+			buf, err := this.readBytes(4)
+			if err != nil {
+				return 0, false, err
+			}
+			return uint64(binary.BigEndian.Uint32(buf)), false, nil
+		case 0x81:
+			buf, err := this.readBytes(8)
+			if err != nil {
+				return 0, false, err
+			}
+			return binary.BigEndian.Uint64(buf), false, nil
+		}
+		return 0, false, fmt.Errorf("未知的长度编码: 0x%X", b)
+	default:
+		return uint64(b & 0x3F), true, nil
+	}
+}
+
+func (this *Parser) readLength() (uint64, error) {
+	length, encoded, err := this.readLengthWithEncoding()
+	if err != nil {
+		return 0, err
+	}
+	if encoded {
+		return 0, fmt.Errorf("此处不应出现特殊编码的字符串")
+	}
+	return length, nil
+}
+
+// readString 读取完整的字符串，整数编码的字符串转为十进制文本
+func (this *Parser) readString() ([]byte, error) {
+	length, encoded, err := this.readLengthWithEncoding()
+	if err != nil {
+		return nil, err
+	}
+	if !encoded {
+		return this.readBytes(int64(length))
+	}
+
+	switch length {
+	case encInt8, encInt16, encInt32:
+		value, err := this.readEncodedInt(length)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatInt(value, 10)), nil
+	case encLZF:
+		compressedLen, err := this.readLength()
+		if err != nil {
+			return nil, err
+		}
+		rawLen, err := this.readLength()
+		if err != nil {
+			return nil, err
+		}
+		compressed, err := this.readBytes(int64(compressedLen))
+		if err != nil {
+			return nil, err
+		}
+		return lzfDecompress(compressed, int(rawLen))
+	default:
+		return nil, fmt.Errorf("未知的字符串编码: %d", length)
+	}
+}
+
+// skipString 跳过字符串，返回其原始长度，不解压、不分配内存
+func (this *Parser) skipString() (int64, error) {
+	length, _, err := this.skipStringInfo()
+	return length, err
+}
+
+func (this *Parser) skipStringInfo() (int64, bool, error) {
+	length, encoded, err := this.readLengthWithEncoding()
+	if err != nil {
+		return 0, false, err
+	}
+	if !encoded {
+		return int64(length), false, this.skip(int64(length))
+	}
+
+	switch length {
+	case encInt8, encInt16, encInt32:
+		value, err := this.readEncodedInt(length)
+		if err != nil {
+			return 0, false, err
+		}
+		return int64(len(strconv.FormatInt(value, 10))), true, nil
+	case encLZF:
+		compressedLen, err := this.readLength()
+		if err != nil {
+			return 0, false, err
+		}
+		rawLen, err := this.readLength()
+		if err != nil {
+			return 0, false, err
+		}
+		return int64(rawLen), false, this.skip(int64(compressedLen))
+	default:
+		return 0, false, fmt.Errorf("未知的字符串编码: %d", length)
+	}
+}
+
+func (this *Parser) readEncodedInt(encoding uint64) (int64, error) {
+	size := int64(1) << encoding // int8、int16、int32分别为1、2、4字节
+	buf, err := this.readBytes(size)
+	if err != nil {
+		return 0, err
+	}
+	switch encoding {
+	case encInt8:
+		return int64(int8(buf[0])), nil
+	case encInt16:
+		return int64(int16(binary.LittleEndian.Uint16(buf))), nil
+	default:
+		return int64(int32(binary.LittleEndian.Uint32(buf))), nil
+	}
+}
+
+func (this *Parser) readMillisecondTime() (int64, error) {
+	buf, err := this.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(buf)), nil
+}
+
+func (this *Parser) readByte() (byte, error) {
+	b, err := this.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	this.offset++
+	return b, nil
+}
+
+func (this *Parser) readBytes(n int64) ([]byte, error) {
+	if n < 0 || n > math.MaxInt32 {
+		return nil, fmt.Errorf("长度超出范围: %d", n)
+	}
+	buf := make([]byte, n)
+	read, err := io.ReadFull(this.r, buf)
+	this.offset += int64(read)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (this *Parser) skip(n int64) error {
+	if n < 0 {
+		return fmt.Errorf("长度超出范围: %d", n)
+	}
+	skipped, err := this.r.Discard(int(n))
+	this.offset += int64(skipped)
+	return err
+}
+
+// moduleName 从模块ID的高54位解出9个字符的模块名
+func moduleName(moduleId uint64) string {
+	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	name := make([]byte, 9)
+	for i := range name {
+		name[i] = charset[(moduleId>>(64-6*uint(i+1)))&63]
+	}
+	return string(name)
+}
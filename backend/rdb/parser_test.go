@@ -0,0 +1,242 @@
+package rdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// rdbLength RDB长度编码
+func rdbLength(n int) []byte {
+	switch {
+	case n < 1<<6:
+		return []byte{byte(n)}
+	case n < 1<<14:
+		return []byte{0x40 | byte(n>>8), byte(n)}
+	default:
+		buf := []byte{0x80, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(buf[1:], uint32(n))
+		return buf
+	}
+}
+
+// rdbString 长度前缀的原始字符串
+func rdbString(s []byte) []byte {
+	return append(rdbLength(len(s)), s...)
+}
+
+// rdbLZFString LZF压缩的字符串
+func rdbLZFString(compressed []byte, rawLen int) []byte {
+	buf := []byte{0xC0 | encLZF}
+	buf = append(buf, rdbLength(len(compressed))...)
+	buf = append(buf, rdbLength(rawLen)...)
+	return append(buf, compressed...)
+}
+
+// ziplist 构造ziplist，count为头部记录的元素数量（0xFFFF表示需要遍历）
+func ziplist(count uint16, values ...string) []byte {
+	buf := make([]byte, 10)
+	binary.LittleEndian.PutUint16(buf[8:], count)
+	for _, value := range values {
+		buf = append(buf, 0, byte(len(value))) // prevlen与6位长度的字符串编码
+		buf = append(buf, value...)
+	}
+	buf = append(buf, 0xFF)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(len(buf)))
+	return buf
+}
+
+// listpackEntry listpack元素：编码与数据，之后补上指定字节数的backlen
+func listpackEntry(data []byte, backlen int) []byte {
+	return append(data, make([]byte, backlen)...)
+}
+
+// listpack 构造listpack，count为头部记录的元素数量（0xFFFF表示需要遍历）
+func listpack(count uint16, entries ...[]byte) []byte {
+	buf := make([]byte, 6)
+	binary.LittleEndian.PutUint16(buf[4:], count)
+	for _, entry := range entries {
+		buf = append(buf, entry...)
+	}
+	buf = append(buf, 0xFF)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(len(buf)))
+	return buf
+}
+
+// rdbKey 值类型、Key名与值
+func rdbKey(valueType byte, key string, value ...[]byte) []byte {
+	buf := append([]byte{valueType}, rdbString([]byte(key))...)
+	for _, v := range value {
+		buf = append(buf, v...)
+	}
+	return buf
+}
+
+func TestListpackBacklenSize(t *testing.T) {
+	// 与Redis的lpEncodeBacklenBytes一致
+	cases := map[int]int{
+		1:         1,
+		127:       1,
+		128:       2,
+		16382:     2,
+		16383:     3,
+		2097150:   3,
+		2097151:   4,
+		268435454: 4,
+		268435455: 5,
+	}
+	for size, want := range cases {
+		if got := listpackBacklenSize(size); got != want {
+			t.Errorf("listpackBacklenSize(%d) = %d，期望%d", size, got, want)
+		}
+	}
+}
+
+func TestListpackCountWalk(t *testing.T) {
+	// 32位长度的字符串，编码与数据共16383字节，backlen为3字节
+	big := []byte{0xF0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(big[1:], 16383-5)
+	big = append(big, bytes.Repeat([]byte("x"), 16383-5)...)
+
+	blob := listpack(0xFFFF,
+		listpackEntry([]byte{0x05}, 1),                         // 7位整数
+		listpackEntry(append([]byte{0x83}, "abc"...), 1),       // 6位长度的字符串
+		listpackEntry(big, 3),                                  // 处于backlen边界的大元素
+		listpackEntry([]byte{0xF1, 0x01, 0x02}, 1),             // 16位整数
+		listpackEntry(append([]byte{0xE0, 0x03}, "xyz"...), 1), // 12位长度的字符串
+	)
+	count, err := listpackCount(blob)
+	if err != nil {
+		t.Fatalf("listpackCount失败: %v", err)
+	}
+	if count != 5 {
+		t.Fatalf("listpackCount = %d，期望5", count)
+	}
+}
+
+func TestZiplistCountWalk(t *testing.T) {
+	count, err := ziplistCount(ziplist(0xFFFF, "a", "bb", "ccc"))
+	if err != nil {
+		t.Fatalf("ziplistCount失败: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("ziplistCount = %d，期望3", count)
+	}
+}
+
+func TestLzfDecompress(t *testing.T) {
+	cases := []struct {
+		in   []byte
+		want string
+	}{
+		{[]byte{0x02, 'a', 'b', 'c', 0x80, 0x02}, "abcabcabc"},         // 短回溯引用
+		{[]byte{0x00, 'a', 0xE0, 0x00, 0x00}, strings.Repeat("a", 10)}, // 扩展长度的回溯引用
+	}
+	for _, c := range cases {
+		out, err := lzfDecompress(c.in, len(c.want))
+		if err != nil {
+			t.Fatalf("lzfDecompress失败: %v", err)
+		}
+		if string(out) != c.want {
+			t.Fatalf("lzfDecompress = %q，期望%q", out, c.want)
+		}
+	}
+
+	if _, err := lzfDecompress([]byte{0x20, 0x05}, 3); err == nil {
+		t.Fatalf("越界的回溯引用应返回错误")
+	}
+}
+
+func TestParseFixture(t *testing.T) {
+	var rdb []byte
+	rdb = append(rdb, "REDIS0012"...)
+	rdb = append(rdb, opAux)
+	rdb = append(rdb, rdbString([]byte("redis-ver"))...)
+	rdb = append(rdb, rdbString([]byte("7.4.0"))...)
+	rdb = append(rdb, opSelectDB, 0)
+	rdb = append(rdb, opResizeDB, 9, 1)
+
+	// 带毫秒过期时间的普通字符串
+	expire := make([]byte, 8)
+	binary.LittleEndian.PutUint64(expire, 1700000000000)
+	rdb = append(rdb, opExpireTimeMs)
+	rdb = append(rdb, expire...)
+	rdb = append(rdb, rdbKey(typeString, "str", rdbString([]byte("hello")))...)
+
+	// 整数编码的字符串
+	rdb = append(rdb, rdbKey(typeString, "int", []byte{0xC0 | encInt8, 42})...)
+
+	// LZF压缩的字符串，只跳过不解压
+	rdb = append(rdb, rdbKey(typeString, "lzf", rdbLZFString([]byte{0x00, 'a', 0xE0, 0x00, 0x00}, 10))...)
+
+	// 需要遍历计数的ziplist hash
+	rdb = append(rdb, rdbKey(typeHashZiplist, "hash", rdbString(ziplist(0xFFFF, "f1", "v1", "f2", "v2")))...)
+
+	// LZF压缩的intset：int16编码的4个0
+	intsetLZF := []byte{0x07, 0x02, 0, 0, 0, 0x04, 0, 0, 0, 0xC0, 0x00}
+	rdb = append(rdb, rdbKey(typeSetIntset, "intset", rdbLZFString(intsetLZF, 16))...)
+
+	// quicklist v2：一个listpack节点与一个单独的大元素节点
+	rdb = append(rdb, rdbKey(typeListQuicklist2, "list",
+		rdbLength(2),
+		rdbLength(2), rdbString(listpack(2, listpackEntry([]byte{0x01}, 1), listpackEntry([]byte{0x02}, 1))),
+		rdbLength(1), rdbString([]byte("plain")),
+	)...)
+
+	// stream v3：一个节点、一个消费组（含一条待确认消息）与一个消费者
+	rdb = append(rdb, rdbKey(typeStreamListpacks3, "stream",
+		rdbLength(1), rdbString(make([]byte, 16)), rdbString(listpack(0)),
+		rdbLength(3),               // 消息数量
+		rdbLength(1), rdbLength(2), // last_id
+		rdbLength(1), rdbLength(0), // first_id
+		rdbLength(0), rdbLength(0), // max_deleted_entry_id
+		rdbLength(3), // entries_added
+		rdbLength(1), rdbString([]byte("group")),
+		rdbLength(1), rdbLength(2), // 消费组last_id
+		rdbLength(3), // entries_read
+		rdbLength(1), make([]byte, 16+8), rdbLength(1),
+		rdbLength(1), rdbString([]byte("consumer")), make([]byte, 8+8),
+		rdbLength(1), make([]byte, 16),
+	)...)
+
+	// 放在最后，前面任何一个Key解析错位都会导致它读不出来
+	rdb = append(rdb, rdbKey(typeString, "tail", rdbString([]byte("end")))...)
+	rdb = append(rdb, opEOF)
+	rdb = append(rdb, make([]byte, 8)...)
+
+	var entries []*Entry
+	header, err := NewParser(bytes.NewReader(rdb)).Parse(func(entry *Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if header.Version != 12 || header.Aux["redis-ver"] != "7.4.0" {
+		t.Fatalf("文件信息错误: %+v", header)
+	}
+
+	want := []Entry{
+		{Key: "str", Type: "string", Encoding: "raw", Elements: 5, ExpireAt: 1700000000000},
+		{Key: "int", Type: "string", Encoding: "int", Elements: 2},
+		{Key: "lzf", Type: "string", Encoding: "raw", Elements: 10},
+		{Key: "hash", Type: "hash", Encoding: "ziplist", Elements: 2},
+		{Key: "intset", Type: "set", Encoding: "intset", Elements: 4},
+		{Key: "list", Type: "list", Encoding: "quicklist", Elements: 3},
+		{Key: "stream", Type: "stream", Encoding: "stream", Elements: 3},
+		{Key: "tail", Type: "string", Encoding: "raw", Elements: 3},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("解析出%d个Key，期望%d个", len(entries), len(want))
+	}
+	for i, w := range want {
+		got := entries[i]
+		if got.Key != w.Key || got.Type != w.Type || got.Encoding != w.Encoding || got.Elements != w.Elements || got.ExpireAt != w.ExpireAt {
+			t.Errorf("第%d个Key = %+v，期望%+v", i, *got, w)
+		}
+		if got.Size <= 0 {
+			t.Errorf("Key %s 的大小应大于0", got.Key)
+		}
+	}
+}
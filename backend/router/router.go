@@ -2,6 +2,7 @@ package router
 
 import (
 	"ev-plugin/backend/api"
+	"ev-plugin/backend/job"
 	"ev-plugin/backend/response"
 
 	"github.com/1340691923/eve-plugin-sdk-go/backend/web_engine"
@@ -10,15 +11,19 @@ import (
 type WebServer struct {
 	engine          *web_engine.WebEngine
 	redisController *api.RedisController
+	jobController   *api.JobController
 }
 
 // 依赖注入
 func NewWebServer(app *web_engine.WebEngine) *WebServer {
 	baseController := api.NewBaseController(response.NewResponse())
-	redisController := api.NewRedisController(baseController)
+	jobManager := job.NewManager()
+	redisController := api.NewRedisController(baseController, jobManager)
+	jobController := api.NewJobController(baseController, jobManager)
 	return &WebServer{
 		engine:          app,
 		redisController: redisController,
+		jobController:   jobController,
 	}
 }
 
@@ -30,12 +35,63 @@ func NewRouter(engine *web_engine.WebEngine) {
 
 	group.POST(false, "获取redis的key列表", "/RedisKeys", webSvr.redisController.GetAllKeysAction)
 	group.POST(false, "获取redis信息概览", "/RedisInfoOverview", webSvr.redisController.GetInfoOverviewAction)
+	group.POST(false, "获取redis慢日志", "/RedisSlowlog", webSvr.redisController.GetSlowlogAction)
+	group.POST(false, "获取redis慢日志条数", "/RedisSlowlogLen", webSvr.redisController.GetSlowlogLenAction)
+	group.POST(true, "清空redis慢日志", "/RedisSlowlogReset", webSvr.redisController.ResetSlowlogAction)
+	group.POST(false, "获取redis客户端列表", "/RedisClientList", webSvr.redisController.GetClientListAction)
+	group.POST(true, "断开redis客户端连接", "/RedisClientKill", webSvr.redisController.KillClientAction)
+	group.POST(true, "暂停redis客户端", "/RedisClientPause", webSvr.redisController.PauseClientsAction)
+	group.POST(true, "恢复redis客户端", "/RedisClientUnpause", webSvr.redisController.UnpauseClientsAction)
 	group.POST(false, "获取redis数据库列表", "/RedisDatabases", webSvr.redisController.GetDatabasesAction)
 	group.POST(false, "获取redis内存分析", "/RedisMemoryAnalysis", webSvr.redisController.GetMemoryAnalysisAction)
 	group.POST(false, "搜索redis key", "/RedisSearchKeys", webSvr.redisController.SearchKeysAction)
 	group.POST(false, "获取redis key详情", "/RedisKeyDetail", webSvr.redisController.GetKeyDetailAction)
 	group.POST(true, "删除redis key", "/RedisDeleteKey", webSvr.redisController.DeleteKeyAction)
+	group.POST(false, "预览按模式批量删除redis key", "/RedisBulkDeletePreview", webSvr.redisController.BulkDeletePreviewAction)
+	group.POST(true, "按模式批量删除redis key", "/RedisBulkDelete", webSvr.redisController.BulkDeleteAction)
+	group.POST(true, "批量设置redis key过期时间", "/RedisBulkExpire", webSvr.redisController.BulkExpireAction)
+	group.POST(true, "跨数据源迁移redis key", "/RedisMigrateKeys", webSvr.redisController.MigrateKeysAction)
+	group.POST(true, "导入JSON Lines格式的redis key", "/RedisImportKeys", webSvr.redisController.ImportKeysAction)
+	group.POST(true, "导入redis命令文件", "/RedisImportCommands", webSvr.redisController.ImportCommandsAction)
+	group.POST(true, "创建redis key快照", "/RedisSnapshotCreate", webSvr.redisController.CreateSnapshotAction)
+	group.POST(true, "从快照恢复redis key", "/RedisSnapshotRestore", webSvr.redisController.RestoreSnapshotAction)
+	group.POST(true, "删除redis key快照", "/RedisSnapshotDelete", webSvr.redisController.DeleteSnapshotAction)
+	group.POST(false, "获取redis key快照列表", "/RedisSnapshotList", webSvr.redisController.ListSnapshotsAction)
+	group.POST(false, "获取redis key快照详情", "/RedisSnapshotDetail", webSvr.redisController.SnapshotDetailAction)
 	group.POST(true, "设置redis key", "/RedisSetKey", webSvr.redisController.SetKeyAction)
+	group.POST(true, "重命名redis key", "/RedisRenameKey", webSvr.redisController.RenameKeyAction)
+	group.POST(true, "复制redis key", "/RedisCopyKey", webSvr.redisController.CopyKeyAction)
+	group.POST(true, "移动redis key到其他库", "/RedisMoveKey", webSvr.redisController.MoveKeyAction)
 	group.POST(false, "批量获取keys内存分析", "/RedisBatchMemoryAnalysis", webSvr.redisController.BatchGetMemoryAnalysisAction)
+	group.POST(false, "获取redis key命名空间树", "/RedisNamespaceTree", webSvr.redisController.GetNamespaceTreeAction)
+	group.POST(false, "获取redis大key分析", "/RedisBigKeys", webSvr.redisController.GetBigKeysAction)
+	group.POST(false, "导出redis大key分析结果", "/RedisBigKeysExport", webSvr.redisController.ExportBigKeysAction)
+	group.POST(false, "导出redis key为JSON Lines", "/RedisExportKeys", webSvr.redisController.ExportKeysAction)
+	group.POST(false, "获取redis热key分析", "/RedisHotKeys", webSvr.redisController.GetHotKeysAction)
+	group.POST(false, "获取redis key过期时间分布分析", "/RedisTtlAnalysis", webSvr.redisController.GetTtlAnalysisAction)
+	group.POST(true, "离线分析redis RDB文件", "/RedisRdbAnalysis", webSvr.redisController.RdbAnalysisAction)
+	group.POST(false, "对比redis keyspace差异", "/RedisKeyspaceDiff", webSvr.redisController.KeyspaceDiffAction)
+	group.POST(true, "redis stream追加消息", "/RedisStreamAdd", webSvr.redisController.StreamAddAction)
+	group.POST(true, "redis stream裁剪", "/RedisStreamTrim", webSvr.redisController.StreamTrimAction)
+	group.POST(true, "redis stream删除消息", "/RedisStreamDelete", webSvr.redisController.StreamDeleteAction)
+	group.POST(true, "创建redis stream消费组", "/RedisStreamGroupCreate", webSvr.redisController.StreamGroupCreateAction)
+	group.POST(true, "删除redis stream消费组", "/RedisStreamGroupDestroy", webSvr.redisController.StreamGroupDestroyAction)
+	group.POST(true, "设置redis hash字段", "/RedisHashSet", webSvr.redisController.HashSetAction)
+	group.POST(true, "删除redis hash字段", "/RedisHashDelete", webSvr.redisController.HashDeleteAction)
+	group.POST(true, "修改redis list元素", "/RedisListSet", webSvr.redisController.ListSetAction)
+	group.POST(true, "插入redis list元素", "/RedisListInsert", webSvr.redisController.ListInsertAction)
+	group.POST(true, "删除redis list元素", "/RedisListRemove", webSvr.redisController.ListRemoveAction)
+	group.POST(true, "添加redis set成员", "/RedisSetAdd", webSvr.redisController.SetAddAction)
+	group.POST(true, "删除redis set成员", "/RedisSetRemove", webSvr.redisController.SetRemoveAction)
+	group.POST(true, "添加redis zset成员", "/RedisZSetAdd", webSvr.redisController.ZSetAddAction)
+	group.POST(true, "增加redis zset成员分数", "/RedisZSetIncrBy", webSvr.redisController.ZSetIncrByAction)
+	group.POST(true, "删除redis zset成员", "/RedisZSetRemove", webSvr.redisController.ZSetRemoveAction)
+
+	group.POST(false, "启动redis后台任务", "/RedisJobStart", webSvr.jobController.StartJobAction)
+	group.POST(false, "获取redis后台任务状态", "/RedisJobStatus", webSvr.jobController.JobStatusAction)
+	group.POST(true, "取消redis后台任务", "/RedisJobCancel", webSvr.jobController.CancelJobAction)
+	group.POST(true, "继续redis后台任务", "/RedisJobResume", webSvr.jobController.ResumeJobAction)
+	group.POST(false, "获取redis后台任务结果", "/RedisJobResult", webSvr.jobController.JobResultAction)
+	group.POST(false, "获取redis后台任务列表", "/RedisJobList", webSvr.jobController.JobListAction)
 
 }
@@ -0,0 +1,147 @@
+// Key快照的持久化
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/1340691923/eve-plugin-sdk-go/ev_api"
+)
+
+// 快照状态
+const (
+	StatusCreating = "creating" // 创建中
+	StatusReady    = "ready"    // 可用于恢复
+	StatusFailed   = "failed"   // 创建失败，明细已清理
+)
+
+const snapshotColumns = "id, name, es_connect, db_index, user_id, pattern, job_id, status, key_count, total_bytes, " +
+	"message, created_at, finished_at"
+
+// Snapshot 快照记录
+type Snapshot struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	EsConnect  int    `json:"es_connect"`
+	DbIndex    int    `json:"db_index"`
+	UserId     int    `json:"user_id"`
+	Pattern    string `json:"pattern"`
+	JobId      string `json:"job_id"`
+	Status     string `json:"status"`
+	KeyCount   int64  `json:"key_count"`
+	TotalBytes int64  `json:"total_bytes"`
+	Message    string `json:"message"`
+	CreatedAt  int64  `json:"created_at"`
+	FinishedAt int64  `json:"finished_at"`
+}
+
+// Key 快照中的单个Key
+type Key struct {
+	ID         int64  `json:"id"`
+	SnapshotId string `json:"snapshot_id"`
+	KeyName    string `json:"key_name"`
+	Pttl       int64  `json:"pttl"`    // 快照时的剩余TTL（毫秒），0表示不过期
+	Size       int64  `json:"size"`    // DUMP序列化值的字节数
+	Payload    string `json:"payload"` // 十六进制DUMP序列化值
+}
+
+// Store 快照的持久化，使用插件自身的数据库（表结构见migrate.V0_0_3）
+type Store struct {
+}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+func (this *Store) Insert(ctx context.Context, snapshot *Snapshot) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"INSERT INTO redis_snapshot ("+snapshotColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		snapshot.ID, snapshot.Name, snapshot.EsConnect, snapshot.DbIndex, snapshot.UserId, snapshot.Pattern,
+		snapshot.JobId, snapshot.Status, snapshot.KeyCount, snapshot.TotalBytes, snapshot.Message,
+		snapshot.CreatedAt, snapshot.FinishedAt,
+	)
+	return err
+}
+
+func (this *Store) Update(ctx context.Context, snapshot *Snapshot) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"UPDATE redis_snapshot SET job_id = ?, status = ?, key_count = ?, total_bytes = ?, message = ?, finished_at = ? WHERE id = ?",
+		snapshot.JobId, snapshot.Status, snapshot.KeyCount, snapshot.TotalBytes, snapshot.Message,
+		snapshot.FinishedAt, snapshot.ID,
+	)
+	return err
+}
+
+func (this *Store) Get(ctx context.Context, id string) (*Snapshot, error) {
+	var snapshots []*Snapshot
+	err := ev_api.GetEvApi().StoreSelect(ctx, &snapshots,
+		"SELECT "+snapshotColumns+" FROM redis_snapshot WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("快照不存在: %s", id)
+	}
+	return snapshots[0], nil
+}
+
+// List 按创建时间倒序列出数据源的快照，esConnect为0时列出全部
+func (this *Store) List(ctx context.Context, esConnect int, limit int) ([]*Snapshot, error) {
+	var snapshots []*Snapshot
+	var err error
+	if esConnect == 0 {
+		err = ev_api.GetEvApi().StoreSelect(ctx, &snapshots,
+			"SELECT "+snapshotColumns+" FROM redis_snapshot ORDER BY created_at DESC LIMIT ?", limit)
+	} else {
+		err = ev_api.GetEvApi().StoreSelect(ctx, &snapshots,
+			"SELECT "+snapshotColumns+" FROM redis_snapshot WHERE es_connect = ? ORDER BY created_at DESC LIMIT ?",
+			esConnect, limit)
+	}
+	return snapshots, err
+}
+
+// InsertKeys 批量写入快照明细
+func (this *Store) InsertKeys(ctx context.Context, keys []*Key) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys)*5)
+	for _, key := range keys {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, key.SnapshotId, key.KeyName, key.Pttl, key.Size, key.Payload)
+	}
+	_, err := ev_api.GetEvApi().StoreExec(ctx,
+		"INSERT INTO redis_snapshot_key (snapshot_id, key_name, pttl, size, payload) VALUES "+
+			strings.Join(placeholders, ", "), args...)
+	return err
+}
+
+// Keys 按ID顺序分页获取快照明细，afterId为上一页最后一条的ID，withPayload为false时不返回序列化值
+func (this *Store) Keys(ctx context.Context, snapshotId string, afterId int64, limit int, withPayload bool) ([]*Key, error) {
+	columns := "id, snapshot_id, key_name, pttl, size"
+	if withPayload {
+		columns += ", payload"
+	}
+	var keys []*Key
+	err := ev_api.GetEvApi().StoreSelect(ctx, &keys,
+		"SELECT "+columns+" FROM redis_snapshot_key WHERE snapshot_id = ? AND id > ? ORDER BY id LIMIT ?",
+		snapshotId, afterId, limit)
+	return keys, err
+}
+
+// DeleteKeys 删除快照明细
+func (this *Store) DeleteKeys(ctx context.Context, snapshotId string) error {
+	_, err := ev_api.GetEvApi().StoreExec(ctx, "DELETE FROM redis_snapshot_key WHERE snapshot_id = ?", snapshotId)
+	return err
+}
+
+// Delete 删除快照及其明细
+func (this *Store) Delete(ctx context.Context, id string) error {
+	if err := this.DeleteKeys(ctx, id); err != nil {
+		return err
+	}
+	_, err := ev_api.GetEvApi().StoreExec(ctx, "DELETE FROM redis_snapshot WHERE id = ?", id)
+	return err
+}
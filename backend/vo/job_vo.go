@@ -0,0 +1,33 @@
+package vo
+
+import "encoding/json"
+
+// 后台任务信息VO
+type JobInfo struct {
+	JobId      string  `json:"jobId"`      // 任务ID
+	Kind       string  `json:"kind"`       // 任务类型
+	Status     string  `json:"status"`     // 任务状态 (pending, running, succeeded, failed, cancelled, interrupted)
+	EsConnect  int     `json:"esConnect"`  // 数据源连接ID
+	Database   int     `json:"database"`   // Redis数据库索引
+	Total      int64   `json:"total"`      // 预计处理总量，0表示未知
+	Processed  int64   `json:"processed"`  // 已处理数量
+	Failed     int64   `json:"failed"`     // 处理失败数量
+	Progress   float64 `json:"progress"`   // 进度百分比（0-100），总量未知时为0
+	Message    string  `json:"message"`    // 当前进度说明
+	Error      string  `json:"error"`      // 失败原因
+	CreatedAt  int64   `json:"createdAt"`  // 创建时间（unix秒）
+	UpdatedAt  int64   `json:"updatedAt"`  // 最后更新时间（unix秒）
+	FinishedAt int64   `json:"finishedAt"` // 结束时间（unix秒），未结束为0
+	ResumedBy  string  `json:"resumedBy"`  // 继续执行本任务的新任务ID，为空表示尚未被继续
+}
+
+// 后台任务列表响应VO
+type JobListResponse struct {
+	Jobs []JobInfo `json:"jobs"` // 任务列表，按创建时间倒序
+}
+
+// 后台任务结果响应VO
+type JobResultResponse struct {
+	Job    JobInfo         `json:"job"`    // 任务信息
+	Result json.RawMessage `json:"result"` // 任务结果，结构由任务类型决定
+}
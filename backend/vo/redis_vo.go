@@ -7,8 +7,155 @@ type RedisKeysResponse struct {
 
 // Redis信息总览响应VO
 type RedisInfoResponse struct {
-	Info     map[string]string        `json:"info"`     // Redis info信息
-	Keyspace []map[string]interface{} `json:"keyspace"` // 各数据库的key统计信息
+	Info         map[string]string            `json:"info"`         // Redis info信息（扁平结构，不含commandstats/errorstats/latencystats）
+	Keyspace     []map[string]interface{}     `json:"keyspace"`     // 各数据库的key统计信息
+	Sections     map[string]map[string]string `json:"sections"`     // 按分段保存的原始INFO信息
+	Server       RedisInfoServer              `json:"server"`       // Server分段
+	Clients      RedisInfoClients             `json:"clients"`      // Clients分段
+	Memory       RedisInfoMemory              `json:"memory"`       // Memory分段
+	Persistence  RedisInfoPersistence         `json:"persistence"`  // Persistence分段
+	Stats        RedisInfoStats               `json:"stats"`        // Stats分段
+	Replication  RedisInfoReplication         `json:"replication"`  // Replication分段
+	Cpu          RedisInfoCpu                 `json:"cpu"`          // CPU分段
+	Cluster      RedisInfoCluster             `json:"cluster"`      // Cluster分段
+	Databases    []RedisDatabaseInfo          `json:"databases"`    // Keyspace分段
+	CommandStats []RedisCommandStat           `json:"commandStats"` // Commandstats分段，按耗时倒序
+	ErrorStats   []RedisErrorStat             `json:"errorStats"`   // Errorstats分段，按次数倒序
+	Derived      RedisInfoDerived             `json:"derived"`      // 派生指标
+}
+
+// Redis INFO Server分段
+type RedisInfoServer struct {
+	RedisVersion    string `json:"redisVersion"`    // Redis版本
+	RedisMode       string `json:"redisMode"`       // 运行模式(standalone/cluster/sentinel)
+	Os              string `json:"os"`              // 操作系统
+	ArchBits        int    `json:"archBits"`        // 架构位数
+	ProcessId       int64  `json:"processId"`       // 进程ID
+	TcpPort         int    `json:"tcpPort"`         // 监听端口
+	UptimeInSeconds int64  `json:"uptimeInSeconds"` // 运行时长（秒）
+	UptimeInDays    int64  `json:"uptimeInDays"`    // 运行时长（天）
+	Hz              int    `json:"hz"`              // 后台任务频率
+	ConfigFile      string `json:"configFile"`      // 配置文件路径
+}
+
+// Redis INFO Clients分段
+type RedisInfoClients struct {
+	ConnectedClients int64 `json:"connectedClients"` // 已连接客户端数
+	BlockedClients   int64 `json:"blockedClients"`   // 阻塞中的客户端数
+	MaxClients       int64 `json:"maxClients"`       // 最大客户端数
+	TrackingClients  int64 `json:"trackingClients"`  // 开启tracking的客户端数
+}
+
+// Redis INFO Memory分段
+type RedisInfoMemory struct {
+	UsedMemory            int64   `json:"usedMemory"`            // 已用内存（字节）
+	UsedMemoryHuman       string  `json:"usedMemoryHuman"`       // 已用内存（可读）
+	UsedMemoryRss         int64   `json:"usedMemoryRss"`         // 常驻内存（字节）
+	UsedMemoryPeak        int64   `json:"usedMemoryPeak"`        // 内存峰值（字节）
+	UsedMemoryPeakHuman   string  `json:"usedMemoryPeakHuman"`   // 内存峰值（可读）
+	UsedMemoryLua         int64   `json:"usedMemoryLua"`         // Lua引擎内存（字节）
+	TotalSystemMemory     int64   `json:"totalSystemMemory"`     // 系统总内存（字节）
+	MaxMemory             int64   `json:"maxMemory"`             // maxmemory配置（字节，0表示不限制）
+	MaxMemoryHuman        string  `json:"maxMemoryHuman"`        // maxmemory配置（可读）
+	MaxMemoryPolicy       string  `json:"maxMemoryPolicy"`       // 淘汰策略
+	MemFragmentationRatio float64 `json:"memFragmentationRatio"` // 内存碎片率
+	MemAllocator          string  `json:"memAllocator"`          // 内存分配器
+}
+
+// Redis INFO Persistence分段
+type RedisInfoPersistence struct {
+	Loading                 bool   `json:"loading"`                 // 是否正在加载数据
+	RdbChangesSinceLastSave int64  `json:"rdbChangesSinceLastSave"` // 上次保存后的变更数
+	RdbBgsaveInProgress     bool   `json:"rdbBgsaveInProgress"`     // 是否正在BGSAVE
+	RdbLastSaveTime         int64  `json:"rdbLastSaveTime"`         // 上次保存时间（Unix秒）
+	RdbLastBgsaveStatus     string `json:"rdbLastBgsaveStatus"`     // 上次BGSAVE结果
+	AofEnabled              bool   `json:"aofEnabled"`              // 是否开启AOF
+	AofRewriteInProgress    bool   `json:"aofRewriteInProgress"`    // 是否正在重写AOF
+	AofLastBgrewriteStatus  string `json:"aofLastBgrewriteStatus"`  // 上次AOF重写结果
+	AofLastWriteStatus      string `json:"aofLastWriteStatus"`      // 上次AOF写入结果
+}
+
+// Redis INFO Stats分段
+type RedisInfoStats struct {
+	TotalConnectionsReceived int64   `json:"totalConnectionsReceived"` // 累计接收连接数
+	TotalCommandsProcessed   int64   `json:"totalCommandsProcessed"`   // 累计处理命令数
+	InstantaneousOpsPerSec   int64   `json:"instantaneousOpsPerSec"`   // 当前每秒操作数
+	TotalNetInputBytes       int64   `json:"totalNetInputBytes"`       // 累计网络输入（字节）
+	TotalNetOutputBytes      int64   `json:"totalNetOutputBytes"`      // 累计网络输出（字节）
+	InstantaneousInputKbps   float64 `json:"instantaneousInputKbps"`   // 当前输入速率（KB/s）
+	InstantaneousOutputKbps  float64 `json:"instantaneousOutputKbps"`  // 当前输出速率（KB/s）
+	RejectedConnections      int64   `json:"rejectedConnections"`      // 拒绝的连接数
+	ExpiredKeys              int64   `json:"expiredKeys"`              // 累计过期Key数
+	EvictedKeys              int64   `json:"evictedKeys"`              // 累计淘汰Key数
+	KeyspaceHits             int64   `json:"keyspaceHits"`             // Key命中次数
+	KeyspaceMisses           int64   `json:"keyspaceMisses"`           // Key未命中次数
+	PubsubChannels           int64   `json:"pubsubChannels"`           // 订阅频道数
+	PubsubPatterns           int64   `json:"pubsubPatterns"`           // 订阅模式数
+	LatestForkUsec           int64   `json:"latestForkUsec"`           // 最近一次fork耗时（微秒）
+	TotalErrorReplies        int64   `json:"totalErrorReplies"`        // 累计错误回复数
+}
+
+// Redis INFO Replication分段
+type RedisInfoReplication struct {
+	Role              string                 `json:"role"`              // 角色(master/slave)
+	ConnectedSlaves   int64                  `json:"connectedSlaves"`   // 已连接从节点数
+	MasterHost        string                 `json:"masterHost"`        // 主节点地址（从节点时）
+	MasterPort        int                    `json:"masterPort"`        // 主节点端口（从节点时）
+	MasterLinkStatus  string                 `json:"masterLinkStatus"`  // 与主节点连接状态（从节点时）
+	MasterReplOffset  int64                  `json:"masterReplOffset"`  // 复制偏移量
+	ReplBacklogActive bool                   `json:"replBacklogActive"` // 是否启用复制积压缓冲区
+	ReplBacklogSize   int64                  `json:"replBacklogSize"`   // 复制积压缓冲区大小
+	Replicas          []RedisInfoReplicaInfo `json:"replicas"`          // 从节点列表（主节点时）
+}
+
+// Redis INFO Replication分段中的从节点信息
+type RedisInfoReplicaInfo struct {
+	Ip     string `json:"ip"`     // 从节点IP
+	Port   int    `json:"port"`   // 从节点端口
+	State  string `json:"state"`  // 同步状态
+	Offset int64  `json:"offset"` // 已确认的复制偏移量
+	Lag    int64  `json:"lag"`    // 延迟（秒）
+}
+
+// Redis INFO CPU分段
+type RedisInfoCpu struct {
+	UsedCpuSys          float64 `json:"usedCpuSys"`          // 主进程内核态CPU（秒）
+	UsedCpuUser         float64 `json:"usedCpuUser"`         // 主进程用户态CPU（秒）
+	UsedCpuSysChildren  float64 `json:"usedCpuSysChildren"`  // 子进程内核态CPU（秒）
+	UsedCpuUserChildren float64 `json:"usedCpuUserChildren"` // 子进程用户态CPU（秒）
+}
+
+// Redis INFO Cluster分段
+type RedisInfoCluster struct {
+	ClusterEnabled bool `json:"clusterEnabled"` // 是否开启集群模式
+}
+
+// Redis INFO Commandstats分段单个命令统计
+type RedisCommandStat struct {
+	Command       string  `json:"command"`       // 命令名称（子命令以|分隔）
+	Calls         int64   `json:"calls"`         // 调用次数
+	Usec          int64   `json:"usec"`          // 累计耗时（微秒）
+	UsecPerCall   float64 `json:"usecPerCall"`   // 平均耗时（微秒）
+	RejectedCalls int64   `json:"rejectedCalls"` // 被拒绝次数（Redis 7.0+）
+	FailedCalls   int64   `json:"failedCalls"`   // 执行失败次数（Redis 7.0+）
+}
+
+// Redis INFO Errorstats分段单个错误统计
+type RedisErrorStat struct {
+	Error string `json:"error"` // 错误前缀，如ERR、WRONGTYPE
+	Count int64  `json:"count"` // 出现次数
+}
+
+// Redis INFO派生指标
+type RedisInfoDerived struct {
+	HitRatio              float64 `json:"hitRatio"`              // 命中率（0-1，无访问时为0）
+	MemFragmentationRatio float64 `json:"memFragmentationRatio"` // 内存碎片率（used_memory_rss/used_memory）
+	MemoryUsageRatio      float64 `json:"memoryUsageRatio"`      // maxmemory使用率（0-1，未设置maxmemory时为0）
+	OpsPerSec             int64   `json:"opsPerSec"`             // 当前每秒操作数
+	AvgOpsPerSec          float64 `json:"avgOpsPerSec"`          // 运行期间平均每秒操作数
+	TotalKeys             int64   `json:"totalKeys"`             // 所有数据库Key总数
+	TotalExpires          int64   `json:"totalExpires"`          // 所有数据库设置过期的Key总数
+	FailedCallRatio       float64 `json:"failedCallRatio"`       // 命令失败率（(failed+rejected)/(calls+rejected)）
 }
 
 // Redis数据库信息
@@ -41,13 +188,32 @@ type RedisMemoryAnalysisResponse struct {
 	TotalCount int                  `json:"totalCount"` // 数据库中的总Key数量（估算）
 }
 
+// Redis按类型汇总的内存统计
+type RedisTypeMemoryStat struct {
+	Type      string `json:"type"`      // 数据类型
+	Count     int64  `json:"count"`     // Key数量
+	TotalSize int64  `json:"totalSize"` // 总大小（字节）
+}
+
+// Redis全量内存分析后台任务结果VO
+type RedisMemoryAnalysisJobResult struct {
+	Pattern     string                `json:"pattern"`     // Key匹配模式
+	ScannedKeys int64                 `json:"scannedKeys"` // 扫描到的Key数量
+	TotalSize   int64                 `json:"totalSize"`   // 所有Key的总大小（字节）
+	TypeStats   []RedisTypeMemoryStat `json:"typeStats"`   // 按类型汇总的统计
+	TopKeys     []RedisKeyMemoryInfo  `json:"topKeys"`     // 内存占用最大的Keys，按大小倒序
+}
+
 // Redis Key详情响应VO
 type RedisKeyDetailResponse struct {
-	Key       string      `json:"key"`       // Key名称
-	Type      string      `json:"type"`      // 数据类型
-	SizeBytes int64       `json:"sizeBytes"` // 大小（字节）
-	TTL       int64       `json:"ttl"`       // 过期时间
-	Value     interface{} `json:"value"`     // Key的值（根据类型不同而不同）
+	Key        string      `json:"key"`        // Key名称
+	Type       string      `json:"type"`       // 数据类型
+	SizeBytes  int64       `json:"sizeBytes"`  // 大小（字节）
+	TTL        int64       `json:"ttl"`        // 过期时间
+	Value      interface{} `json:"value"`      // Key的值（根据类型不同而不同），hash/list/set/zset为当前页
+	Total      int64       `json:"total"`      // 元素总数，string为字节长度
+	NextCursor string      `json:"nextCursor"` // 下一页游标，为空表示已读取完毕
+	Version    string      `json:"version"`    // 版本号，由类型、过期时间与值计算得出，保存时用于检测并发修改；仅第一页返回，大Key为cheap:前缀的简化版本号
 }
 
 // Redis操作响应VO
@@ -56,6 +222,13 @@ type RedisOperationResponse struct {
 	Message string `json:"message"` // 操作结果消息
 }
 
+// Redis Key重命名/复制/移动响应VO
+type RedisKeyTransferResponse struct {
+	Key      string `json:"key"`      // 目标Key
+	Database int    `json:"database"` // 目标库
+	Method   string `json:"method"`   // 实际使用的方式 (RENAME, RENAMENX, COPY, MOVE, DUMP/RESTORE)
+}
+
 // Redis Key搜索响应VO (后端搜索)
 type RedisSearchKeysResponse struct {
 	Keys         []RedisKeyMemoryInfo `json:"keys"`         // 匹配的Key信息列表
@@ -64,7 +237,9 @@ type RedisSearchKeysResponse struct {
 	NextCursor   string               `json:"nextCursor"`   // 下一页游标，为"0"表示已到末尾
 	TotalCount   int                  `json:"totalCount"`   // 数据库中的总Key数量（估算）
 	SearchText   string               `json:"searchText"`   // 搜索文本
-	MatchedCount int                  `json:"matchedCount"` // 匹配的总数量（估算）
+	MatchMode    string               `json:"matchMode"`    // 实际使用的匹配模式
+	MatchedCount int                  `json:"matchedCount"` // 本页匹配的数量
+	Partial      bool                 `json:"partial"`      // 是否只扫描了部分keyspace，为true时可用nextCursor继续搜索
 }
 
 // Redis 批量添加 Key 响应 VO
@@ -82,3 +257,544 @@ type RedisBatchMemoryAnalysisResponse struct {
 	ProcessedKeys  int                  `json:"processedKeys"`  // 成功处理的key数量
 	TotalKeys      int                  `json:"totalKeys"`      // 请求处理的key总数
 }
+
+// Redis Key命名空间树节点
+type RedisNamespaceNode struct {
+	Name          string           `json:"name"`          // 节点名称（当前层级的片段）
+	FullPath      string           `json:"fullPath"`      // 完整路径，命名空间节点以分隔符结尾，可作为prefix加载子节点
+	IsLeaf        bool             `json:"isLeaf"`        // 是否为叶子key
+	KeyCount      int64            `json:"keyCount"`      // 节点下的key数量
+	TotalSize     int64            `json:"totalSize"`     // 节点下key的总大小（字节），sampled模式下为按样本均值估算
+	SampledKeys   int64            `json:"sampledKeys"`   // 实际执行了TYPE/MEMORY USAGE的key数量
+	TypeBreakdown map[string]int64 `json:"typeBreakdown"` // 类型分布，sampled模式下为样本中的分布
+	Type          string           `json:"type"`          // 叶子key的数据类型
+	Estimated     bool             `json:"estimated"`     // 超出单次请求的采样上限，未采样，大小按已采样key的平均值估算
+}
+
+// Redis Key命名空间树响应VO
+type RedisNamespaceTreeResponse struct {
+	Prefix        string               `json:"prefix"`        // 父节点前缀
+	Delimiter     string               `json:"delimiter"`     // 命名空间分隔符
+	MemoryMode    string               `json:"memoryMode"`    // 内存统计方式
+	Nodes         []RedisNamespaceNode `json:"nodes"`         // 子节点，按key数量倒序
+	LeafCount     int64                `json:"leafCount"`     // 直接位于该前缀下的叶子key数量
+	ScannedKeys   int64                `json:"scannedKeys"`   // 本次扫描到的key数量
+	NextCursor    string               `json:"nextCursor"`    // 下一次统计的游标，为"0"表示已扫描完成
+	Partial       bool                 `json:"partial"`       // 是否为不完整的统计：只扫描了部分keyspace，或部分节点超出采样上限
+	SampleLimited bool                 `json:"sampleLimited"` // 是否有节点超出采样上限，这些节点的estimated为true
+}
+
+// Redis大Key信息
+type RedisBigKeyInfo struct {
+	Key          string `json:"key"`          // Key名称
+	Type         string `json:"type"`         // 数据类型
+	SizeBytes    int64  `json:"sizeBytes"`    // MEMORY USAGE采样得到的大小（字节），不支持时为估算值
+	Elements     int64  `json:"elements"`     // 元素数量（string为字节长度）
+	OverBytes    bool   `json:"overBytes"`    // 是否超过内存阈值
+	OverElements bool   `json:"overElements"` // 是否超过元素数量阈值
+}
+
+// Redis大Key按类型汇总
+type RedisBigKeyTypeSummary struct {
+	Type          string            `json:"type"`          // 数据类型
+	Count         int64             `json:"count"`         // Key数量
+	TotalSize     int64             `json:"totalSize"`     // 总大小（字节）
+	TotalElements int64             `json:"totalElements"` // 总元素数量
+	BigKeys       int64             `json:"bigKeys"`       // 超过阈值的Key数量
+	TopBySize     []RedisBigKeyInfo `json:"topBySize"`     // 按大小倒序的TopN
+	TopByElements []RedisBigKeyInfo `json:"topByElements"` // 按元素数量倒序的TopN
+}
+
+// Redis大Key分析分页响应VO
+type RedisBigKeysResponse struct {
+	BigKeys       []RedisBigKeyInfo        `json:"bigKeys"`       // 本页超过阈值的Keys
+	TypeSummaries []RedisBigKeyTypeSummary `json:"typeSummaries"` // 本页按类型汇总
+	ScannedKeys   int                      `json:"scannedKeys"`   // 本页扫描的Key数量
+	NextCursor    string                   `json:"nextCursor"`    // 下一页游标，为"0"表示已到末尾
+}
+
+// Redis大Key分析后台任务结果VO
+type RedisBigKeysJobResult struct {
+	Pattern       string                   `json:"pattern"`       // Key匹配模式
+	ScannedKeys   int64                    `json:"scannedKeys"`   // 扫描的Key数量
+	BigKeyCount   int64                    `json:"bigKeyCount"`   // 超过阈值的Key总数
+	BigKeys       []RedisBigKeyInfo        `json:"bigKeys"`       // 超过阈值的Keys，按大小倒序
+	Truncated     bool                     `json:"truncated"`     // bigKeys是否因数量上限被截断
+	TypeSummaries []RedisBigKeyTypeSummary `json:"typeSummaries"` // 按类型汇总
+}
+
+// Redis TTL分布区间
+type RedisTtlBucket struct {
+	Label      string `json:"label"`      // 区间名称，no_expiry表示永不过期
+	MaxSeconds int64  `json:"maxSeconds"` // 区间上限（秒，不含），0表示不封顶，-1表示永不过期
+	Count      int64  `json:"count"`      // Key数量
+	TotalSize  int64  `json:"totalSize"`  // 总大小（字节）
+}
+
+// Redis过期释放预测
+type RedisTtlForecast struct {
+	Keys  int64 `json:"keys"`  // 将过期的Key数量
+	Bytes int64 `json:"bytes"` // 将释放的内存（字节）
+}
+
+// Redis TTL分布统计结果
+type RedisTtlAnalysisResult struct {
+	ScannedKeys      int64                `json:"scannedKeys"`      // 成功分析的Key数量
+	FailedKeys       int64                `json:"failedKeys"`       // 分析失败的Key数量
+	TotalSize        int64                `json:"totalSize"`        // 已分析Key的总大小（字节）
+	ExpiringKeys     int64                `json:"expiringKeys"`     // 设置了过期时间的Key数量
+	NoExpiryKeys     int64                `json:"noExpiryKeys"`     // 永不过期的Key数量
+	NoExpirySize     int64                `json:"noExpirySize"`     // 永不过期Key的总大小（字节）
+	AvgTtlSeconds    int64                `json:"avgTtlSeconds"`    // 设置了过期时间的Key的平均剩余TTL（秒）
+	Buckets          []RedisTtlBucket     `json:"buckets"`          // TTL分布直方图
+	ExpireWithinHour RedisTtlForecast     `json:"expireWithinHour"` // 1小时内将过期
+	ExpireWithinDay  RedisTtlForecast     `json:"expireWithinDay"`  // 1天内将过期
+	TopNoExpiryKeys  []RedisKeyMemoryInfo `json:"topNoExpiryKeys"`  // 最大的永不过期Key，按大小倒序
+}
+
+// Redis TTL分布分析响应VO
+type RedisTtlAnalysisResponse struct {
+	RedisTtlAnalysisResult
+	Mode                string           `json:"mode"`                // 取样方式 (scan, sample)
+	DbSize              int64            `json:"dbSize"`              // 当前库的Key总数
+	NextCursor          string           `json:"nextCursor"`          // 下一页游标，为"0"表示已到末尾（扫描模式）
+	EstimatedWithinHour RedisTtlForecast `json:"estimatedWithinHour"` // 按DBSIZE外推的1小时内释放量（采样模式）
+	EstimatedWithinDay  RedisTtlForecast `json:"estimatedWithinDay"`  // 按DBSIZE外推的1天内释放量（采样模式）
+}
+
+// Redis TTL分布分析后台任务结果VO
+type RedisTtlAnalysisJobResult struct {
+	Pattern string `json:"pattern"` // Key匹配模式
+	RedisTtlAnalysisResult
+}
+
+// Redis热Key信息
+type RedisHotKeyInfo struct {
+	Key         string           `json:"key"`         // Key名称
+	Freq        int64            `json:"freq"`        // OBJECT FREQ返回的LFU访问频率计数（lfu模式）
+	IdleSeconds int64            `json:"idleSeconds"` // OBJECT IDLETIME返回的空闲秒数（idle模式）
+	Calls       int64            `json:"calls"`       // 采样期间的命令调用次数（monitor模式）
+	Commands    map[string]int64 `json:"commands"`    // 按命令统计的调用次数（monitor模式）
+}
+
+// Redis热Key分析响应VO
+type RedisHotKeysResponse struct {
+	Mode            string            `json:"mode"`            // 实际使用的分析方式 (lfu, idle, monitor)
+	MaxmemoryPolicy string            `json:"maxmemoryPolicy"` // 当前maxmemory-policy
+	Keys            []RedisHotKeyInfo `json:"keys"`            // 热Key列表，按热度倒序
+	ScannedKeys     int               `json:"scannedKeys"`     // 本次扫描/解析到的Key数量
+	NextCursor      string            `json:"nextCursor"`      // 下一次扫描的游标，为"0"表示已到末尾（lfu/idle模式）
+	Notice          string            `json:"notice"`          // 分析方式的说明
+}
+
+// Redis Stream消息字段
+type RedisStreamField struct {
+	Field string `json:"field"` // 字段名
+	Value string `json:"value"` // 字段值
+}
+
+// Redis Stream消息
+type RedisStreamEntry struct {
+	Id     string             `json:"id"`     // 消息ID
+	Fields []RedisStreamField `json:"fields"` // 消息字段，保持写入顺序
+}
+
+// Redis Stream消费者信息 (XINFO CONSUMERS)
+type RedisStreamConsumer struct {
+	Name     string `json:"name"`     // 消费者名称
+	Pending  int64  `json:"pending"`  // 已读取未确认的消息数
+	Idle     int64  `json:"idle"`     // 距上次交互的毫秒数
+	Inactive int64  `json:"inactive"` // 距上次成功读取的毫秒数（Redis 7.2+），不支持时为-1
+}
+
+// Redis Stream消费组信息 (XINFO GROUPS)
+type RedisStreamGroup struct {
+	Name            string                `json:"name"`            // 消费组名称
+	Consumers       int64                 `json:"consumers"`       // 消费者数量
+	Pending         int64                 `json:"pending"`         // 已投递未确认的消息数
+	LastDeliveredId string                `json:"lastDeliveredId"` // 最后投递的消息ID
+	Lag             int64                 `json:"lag"`             // 未投递的消息数（Redis 7.0+），不支持时为-1
+	ConsumerList    []RedisStreamConsumer `json:"consumerList"`    // 消费者列表
+}
+
+// Redis Stream详情
+type RedisStreamDetail struct {
+	Length          int64              `json:"length"`          // 消息数量
+	LastGeneratedId string             `json:"lastGeneratedId"` // 最后生成的消息ID
+	FirstEntry      *RedisStreamEntry  `json:"firstEntry"`      // 第一条消息
+	LastEntry       *RedisStreamEntry  `json:"lastEntry"`       // 最后一条消息
+	Entries         []RedisStreamEntry `json:"entries"`         // 当前页消息
+	NextCursor      string             `json:"nextCursor"`      // 下一页起始ID，为空表示已到末尾
+	Groups          []RedisStreamGroup `json:"groups"`          // 消费组列表
+}
+
+// Redis Stream追加消息响应VO
+type RedisStreamAddResponse struct {
+	Id string `json:"id"` // 新消息的ID
+}
+
+// Redis Stream裁剪/删除响应VO
+type RedisStreamAffectedResponse struct {
+	Affected int64 `json:"affected"` // 被删除的消息数量
+}
+
+// Redis元素级操作响应VO
+type RedisElementResponse struct {
+	Affected int64  `json:"affected"`        // 受影响的元素数量，LINSERT/LPUSH/RPUSH为操作后的列表长度
+	Score    string `json:"score,omitempty"` // ZINCRBY后的新分数
+}
+
+// Redis按模式批量删除预览响应VO
+type RedisBulkDeletePreviewResponse struct {
+	Pattern      string   `json:"pattern"`      // 实际使用的SCAN MATCH模式
+	MatchedCount int64    `json:"matchedCount"` // 匹配的Key数量
+	ScannedCount int64    `json:"scannedCount"` // 已扫描的Key数量
+	Sample       []string `json:"sample"`       // 样例Key
+	Complete     bool     `json:"complete"`     // 是否扫描完整个keyspace，为false时matchedCount只是下限
+}
+
+// Redis按模式批量删除任务结果
+type RedisBulkDeleteJobResult struct {
+	Pattern string `json:"pattern"` // 实际使用的SCAN MATCH模式
+	Scanned int64  `json:"scanned"` // 扫描的Key数量
+	Matched int64  `json:"matched"` // 匹配的Key数量
+	Deleted int64  `json:"deleted"` // 实际删除的Key数量（已被其他客户端删除或过期的Key不计入）
+	Failed  int64  `json:"failed"`  // 删除失败的Key数量
+}
+
+// Redis批量设置过期时间结果
+type RedisBulkExpireResult struct {
+	Command string `json:"command"` // 执行的过期命令
+	Scanned int64  `json:"scanned"` // 扫描的Key数量，指定Key列表时为0
+	Matched int64  `json:"matched"` // 处理的Key数量
+	Changed int64  `json:"changed"` // 过期时间实际发生变化的Key数量（Key不存在或条件不满足时不计入）
+	Failed  int64  `json:"failed"`  // 执行失败的Key数量
+}
+
+// Redis批量设置过期时间响应VO - 指定Key列表时同步返回结果，按模式执行时返回后台任务
+type RedisBulkExpireResponse struct {
+	Result *RedisBulkExpireResult `json:"result,omitempty"` // 同步执行的结果
+	Job    *JobInfo               `json:"job,omitempty"`    // 按模式执行的后台任务
+}
+
+// Redis迁移失败的Key
+type RedisMigrateFailure struct {
+	Key   string `json:"key"`   // Key名称
+	Error string `json:"error"` // 失败原因
+}
+
+// Redis跨数据源迁移任务结果VO
+type RedisMigrateJobResult struct {
+	Pattern           string                `json:"pattern"`           // 实际使用的SCAN MATCH模式，指定Key列表时为空
+	TargetConnect     int                   `json:"targetConnect"`     // 目标数据源连接ID
+	TargetDatabase    int                   `json:"targetDatabase"`    // 目标库
+	Scanned           int64                 `json:"scanned"`           // 扫描的Key数量，续跑时包含原任务的数量
+	Matched           int64                 `json:"matched"`           // 处理的Key数量，续跑时包含原任务的数量
+	Migrated          int64                 `json:"migrated"`          // 成功写入目标的Key数量
+	Skipped           int64                 `json:"skipped"`           // 目标已存在而跳过的Key数量
+	Missing           int64                 `json:"missing"`           // 迁移时源Key已不存在的数量
+	Failed            int64                 `json:"failed"`            // 迁移失败的Key数量
+	Failures          []RedisMigrateFailure `json:"failures"`          // 失败明细
+	FailuresTruncated bool                  `json:"failuresTruncated"` // 失败明细是否因数量上限被截断
+}
+
+// Redis Key摘要
+type RedisKeyDigest struct {
+	Type       string `json:"type"`             // 数据类型
+	TtlClass   string `json:"ttlClass"`         // TTL类别 (no_expiry, expiring)
+	Digest     string `json:"digest,omitempty"` // 值摘要，digest_mode为none或未校验时为空
+	Unverified bool   `json:"unverified"`       // Key超过摘要的内存上限，未计算摘要
+}
+
+// Redis两边都存在但不一致的Key
+type RedisKeyDiff struct {
+	Key         string         `json:"key"`         // Key名称
+	Differences []string       `json:"differences"` // 不一致的项 (type, ttl, value)
+	Source      RedisKeyDigest `json:"source"`      // 当前库中的摘要
+	Target      RedisKeyDigest `json:"target"`      // 对比库中的摘要
+}
+
+// Redis keyspace对比任务结果VO
+type RedisKeyspaceDiffResult struct {
+	Pattern           string         `json:"pattern"`           // 实际使用的SCAN MATCH模式
+	DigestMode        string         `json:"digestMode"`        // 值摘要方式
+	TargetConnect     int            `json:"targetConnect"`     // 对比的数据源连接ID
+	TargetDatabase    int            `json:"targetDatabase"`    // 对比的库
+	ScannedSource     int64          `json:"scannedSource"`     // 当前库扫描的Key数量
+	ScannedTarget     int64          `json:"scannedTarget"`     // 对比库扫描的Key数量
+	IdenticalCount    int64          `json:"identicalCount"`    // 两边一致的Key数量
+	OnlyInSourceCount int64          `json:"onlyInSourceCount"` // 只在当前库存在的Key数量
+	OnlyInSource      []string       `json:"onlyInSource"`      // 只在当前库存在的Key
+	OnlyInTargetCount int64          `json:"onlyInTargetCount"` // 只在对比库存在的Key数量
+	OnlyInTarget      []string       `json:"onlyInTarget"`      // 只在对比库存在的Key
+	DifferentCount    int64          `json:"differentCount"`    // 两边都存在但不一致的Key数量
+	Different         []RedisKeyDiff `json:"different"`         // 两边都存在但不一致的Key
+	UnverifiedCount   int64          `json:"unverifiedCount"`   // 类型与TTL一致，但值过大未计算摘要、无法判断是否一致的Key数量
+	Unverified        []string       `json:"unverified"`        // 未校验值的Key
+	FailedKeys        int64          `json:"failedKeys"`        // 对比失败的Key数量
+	Truncated         bool           `json:"truncated"`         // 各类Key列表是否因数量上限被截断
+}
+
+// Redis Key导出行，JSON Lines文件中的一行
+type RedisExportLine struct {
+	Key   string      `json:"key"`   // Key名称
+	Type  string      `json:"type"`  // 数据类型
+	TTL   int64       `json:"ttl"`   // 过期时间（秒），-1表示永不过期
+	Value interface{} `json:"value"` // 值，编码与Key详情一致
+}
+
+// Redis Key导出文件的结尾汇总行，用于区分完整与中断的导出文件
+type RedisExportSummary struct {
+	Summary         bool     `json:"summary"`         // 固定为true，标识汇总行
+	Complete        bool     `json:"complete"`        // 是否完整导出：未中断且没有读取失败的Key
+	Exported        int64    `json:"exported"`        // 已导出的Key数量
+	Failed          int64    `json:"failed"`          // 读取失败而未导出的Key数量
+	FailedKeys      []string `json:"failedKeys"`      // 读取失败的Key
+	FailedTruncated bool     `json:"failedTruncated"` // 读取失败的Key是否因数量上限被截断
+	Error           string   `json:"error,omitempty"` // 导出中断的原因
+}
+
+// Redis导入失败的行
+type RedisImportLineError struct {
+	Line  int    `json:"line"`  // 行号，从1开始
+	Key   string `json:"key"`   // Key名称，无法解析时为空
+	Error string `json:"error"` // 失败原因
+}
+
+// Redis Key导入结果VO
+type RedisImportResult struct {
+	Policy           string                 `json:"policy"`           // Key已存在时的处理策略
+	DryRun           bool                   `json:"dryRun"`           // 是否为只校验不写入
+	Lines            int64                  `json:"lines"`            // 非空行数
+	Imported         int64                  `json:"imported"`         // 写入的Key数量，dry run时为可写入的数量
+	Skipped          int64                  `json:"skipped"`          // 因已存在而跳过的Key数量
+	Conflicts        int64                  `json:"conflicts"`        // 已存在的Key数量
+	Failed           int64                  `json:"failed"`           // 失败的行数
+	StoppedAtLine    int                    `json:"stoppedAtLine"`    // fail策略下终止的行号，0表示未终止
+	Errors           []RedisImportLineError `json:"errors"`           // 失败明细
+	ErrorsTruncated  bool                   `json:"errorsTruncated"`  // 失败明细是否因数量上限被截断
+	SourceIncomplete bool                   `json:"sourceIncomplete"` // 导入文件的汇总行表明导出时中断或有Key读取失败
+}
+
+// Redis Key导入响应VO
+type RedisImportResponse struct {
+	Result *RedisImportResult `json:"result,omitempty"` // dry run的结果
+	Job    *JobInfo           `json:"job,omitempty"`    // 实际导入的后台任务
+}
+
+// Redis命令执行错误汇总，按命令与错误信息分组
+type RedisCommandErrorGroup struct {
+	Command   string `json:"command"`   // 命令名称
+	Error     string `json:"error"`     // 错误信息
+	Count     int64  `json:"count"`     // 出现次数
+	FirstLine int    `json:"firstLine"` // 第一次出现的行号（RESP格式为命令序号）
+}
+
+// Redis命令文件导入结果VO
+type RedisCommandImportResult struct {
+	Format          string                   `json:"format"`          // 实际使用的文件格式
+	Commands        int64                    `json:"commands"`        // 命令总数
+	Succeeded       int64                    `json:"succeeded"`       // 执行成功的命令数量
+	Failed          int64                    `json:"failed"`          // 执行失败的命令数量
+	ErrorSummary    []RedisCommandErrorGroup `json:"errorSummary"`    // 错误汇总，按出现次数倒序
+	ErrorsTruncated bool                     `json:"errorsTruncated"` // 错误种类是否因数量上限被截断
+}
+
+// Redis Key快照信息VO
+type RedisSnapshotInfo struct {
+	SnapshotId string `json:"snapshotId"` // 快照ID
+	Name       string `json:"name"`       // 快照名称
+	EsConnect  int    `json:"esConnect"`  // 数据源连接ID
+	Database   int    `json:"database"`   // Redis数据库索引
+	Pattern    string `json:"pattern"`    // 快照时的匹配模式，指定Key列表时为空
+	JobId      string `json:"jobId"`      // 创建快照的后台任务ID
+	Status     string `json:"status"`     // 快照状态 (creating, ready, failed)
+	KeyCount   int64  `json:"keyCount"`   // Key数量
+	TotalBytes int64  `json:"totalBytes"` // DUMP序列化值总字节数
+	Message    string `json:"message"`    // 进度说明或失败原因
+	CreatedAt  int64  `json:"createdAt"`  // 创建时间（unix秒）
+	FinishedAt int64  `json:"finishedAt"` // 完成时间（unix秒），创建中为0
+}
+
+// Redis Key快照列表响应VO
+type RedisSnapshotListResponse struct {
+	Snapshots []RedisSnapshotInfo `json:"snapshots"` // 快照列表，按创建时间倒序
+}
+
+// Redis Key快照创建响应VO
+type RedisSnapshotCreateResponse struct {
+	Snapshot RedisSnapshotInfo `json:"snapshot"` // 快照信息
+	Job      JobInfo           `json:"job"`      // 创建快照的后台任务
+}
+
+// Redis Key快照中的Key
+type RedisSnapshotKeyInfo struct {
+	Id   int64  `json:"id"`   // 明细ID
+	Key  string `json:"key"`  // Key名称
+	Pttl int64  `json:"pttl"` // 快照时的剩余TTL（毫秒），0表示不过期
+	Size int64  `json:"size"` // DUMP序列化值字节数
+}
+
+// Redis Key快照详情响应VO
+type RedisSnapshotDetailResponse struct {
+	Snapshot    RedisSnapshotInfo      `json:"snapshot"`    // 快照信息
+	Keys        []RedisSnapshotKeyInfo `json:"keys"`        // 本页Key
+	NextAfterId int64                  `json:"nextAfterId"` // 下一页游标，0表示没有更多
+}
+
+// Redis Key快照恢复任务结果VO
+type RedisSnapshotRestoreResult struct {
+	SnapshotId        string                `json:"snapshotId"`        // 快照ID
+	Restored          int64                 `json:"restored"`          // 恢复的Key数量，续跑时包含原任务中断前的数量
+	Failed            int64                 `json:"failed"`            // 恢复失败的Key数量，续跑时包含原任务中断前的数量
+	Failures          []RedisMigrateFailure `json:"failures"`          // 失败明细
+	FailuresTruncated bool                  `json:"failuresTruncated"` // 失败明细是否因数量上限被截断
+}
+
+// Redis RDB中的Key信息
+type RedisRdbKeyInfo struct {
+	Key       string `json:"key"`       // Key名称
+	Database  int    `json:"database"`  // 所在库
+	Type      string `json:"type"`      // 数据类型
+	Encoding  string `json:"encoding"`  // RDB中的存储编码
+	SizeBytes int64  `json:"sizeBytes"` // RDB中序列化后的大小（字节，含Key名）
+	Elements  int64  `json:"elements"`  // 元素数量（string为字节长度）
+	TTL       int64  `json:"ttl"`       // 相对RDB生成时间的剩余过期时间（秒，-1表示永不过期）
+	ExpireAt  int64  `json:"expireAt"`  // 过期时间（unix毫秒），0表示不过期
+}
+
+// Redis RDB按类型与编码汇总的统计
+type RedisRdbEncodingStat struct {
+	Type      string `json:"type"`      // 数据类型
+	Encoding  string `json:"encoding"`  // 存储编码
+	Count     int64  `json:"count"`     // Key数量
+	TotalSize int64  `json:"totalSize"` // 总大小（字节）
+}
+
+// Redis RDB按库汇总的统计
+type RedisRdbDatabaseStat struct {
+	Database     int   `json:"database"`     // 库索引
+	Keys         int64 `json:"keys"`         // Key数量
+	ExpiringKeys int64 `json:"expiringKeys"` // 设置了过期时间的Key数量
+	TotalSize    int64 `json:"totalSize"`    // 总大小（字节）
+}
+
+// Redis RDB按前缀聚合的统计
+type RedisRdbPrefixStat struct {
+	Prefix        string           `json:"prefix"`        // 前缀，以分隔符结尾
+	Keys          int64            `json:"keys"`          // Key数量
+	TotalSize     int64            `json:"totalSize"`     // 总大小（字节）
+	Elements      int64            `json:"elements"`      // 总元素数量
+	TypeBreakdown map[string]int64 `json:"typeBreakdown"` // 类型分布
+}
+
+// Redis RDB离线分析任务结果VO
+type RedisRdbAnalysisResult struct {
+	RdbVersion        int                    `json:"rdbVersion"`        // RDB版本
+	RedisVersion      string                 `json:"redisVersion"`      // 生成RDB的Redis版本
+	CreatedAt         int64                  `json:"createdAt"`         // RDB生成时间（unix秒），文件中没有记录时为文件修改时间
+	FileSize          int64                  `json:"fileSize"`          // 文件大小（字节）
+	TotalKeys         int64                  `json:"totalKeys"`         // Key总数
+	TotalSize         int64                  `json:"totalSize"`         // 所有Key序列化后的总大小（字节）
+	ExpiredKeys       int64                  `json:"expiredKeys"`       // RDB生成时已过期的Key数量
+	Databases         []RedisRdbDatabaseStat `json:"databases"`         // 按库汇总
+	TypeStats         []RedisTypeMemoryStat  `json:"typeStats"`         // 按类型汇总，按大小倒序
+	EncodingStats     []RedisRdbEncodingStat `json:"encodingStats"`     // 按类型与编码汇总，按大小倒序
+	TopKeys           []RedisRdbKeyInfo      `json:"topKeys"`           // 序列化后最大的Keys，按大小倒序
+	TopByElements     []RedisRdbKeyInfo      `json:"topByElements"`     // 元素最多的Keys，按元素数量倒序
+	Delimiter         string                 `json:"delimiter"`         // 前缀聚合的分隔符
+	Prefixes          []RedisRdbPrefixStat   `json:"prefixes"`          // 前缀聚合，按大小倒序
+	PrefixesTruncated bool                   `json:"prefixesTruncated"` // 前缀数量超过统计上限，之后出现的新前缀未统计
+}
+
+// Redis慢日志条目
+type RedisSlowlogEntry struct {
+	Id             int64    `json:"id"`             // 慢日志ID
+	Timestamp      int64    `json:"timestamp"`      // 执行时间（unix秒）
+	DurationMicros int64    `json:"durationMicros"` // 耗时（微秒）
+	Command        string   `json:"command"`        // 命令名称（大写）
+	Args           []string `json:"args"`           // 命令与参数，超长时已被Redis截断
+	Key            string   `json:"key"`            // 命令操作的第一个key，不含key的命令为空
+	KeyPattern     string   `json:"keyPattern"`     // 归一化后的key模式，ID类片段替换为*
+	ClientAddr     string   `json:"clientAddr"`     // 客户端地址（4.0+）
+	ClientName     string   `json:"clientName"`     // 客户端名称（4.0+）
+}
+
+// Redis慢日志按命令与key模式聚合的分组
+type RedisSlowlogGroup struct {
+	Command          string   `json:"command"`          // 命令名称
+	KeyPattern       string   `json:"keyPattern"`       // 归一化后的key模式，不含key的命令为空
+	Count            int64    `json:"count"`            // 条数
+	TotalMicros      int64    `json:"totalMicros"`      // 总耗时（微秒）
+	P50Micros        int64    `json:"p50Micros"`        // 耗时P50（微秒）
+	P99Micros        int64    `json:"p99Micros"`        // 耗时P99（微秒）
+	MaxMicros        int64    `json:"maxMicros"`        // 最大耗时（微秒）
+	FirstTimestamp   int64    `json:"firstTimestamp"`   // 最早出现时间（unix秒）
+	LastTimestamp    int64    `json:"lastTimestamp"`    // 最近出现时间（unix秒）
+	ClientCount      int      `json:"clientCount"`      // 涉及的客户端地址数量
+	Clients          []string `json:"clients"`          // 涉及的客户端地址
+	ClientsTruncated bool     `json:"clientsTruncated"` // 客户端地址是否因数量上限被截断
+}
+
+// Redis慢日志响应VO
+type RedisSlowlogResponse struct {
+	Entries          []RedisSlowlogEntry `json:"entries"`          // 慢日志，按ID倒序（最新在前）
+	Groups           []RedisSlowlogGroup `json:"groups"`           // 按命令与key模式聚合，按总耗时倒序
+	Len              int64               `json:"len"`              // 服务端当前保存的慢日志条数
+	SlowerThanMicros int64               `json:"slowerThanMicros"` // slowlog-log-slower-than配置（微秒），读取失败时为0
+	MaxLen           int64               `json:"maxLen"`           // slowlog-max-len配置，读取失败时为0
+}
+
+// Redis慢日志条数响应VO
+type RedisSlowlogLenResponse struct {
+	Len int64 `json:"len"` // 服务端当前保存的慢日志条数
+}
+
+// Redis客户端连接信息
+type RedisClientInfo struct {
+	Id      int64  `json:"id"`      // 客户端ID
+	Addr    string `json:"addr"`    // 客户端地址
+	Laddr   string `json:"laddr"`   // 连接的服务端地址（6.2+）
+	Ip      string `json:"ip"`      // 客户端IP，由addr解析
+	Name    string `json:"name"`    // 客户端名称（CLIENT SETNAME）
+	Age     int64  `json:"age"`     // 连接时长（秒）
+	Idle    int64  `json:"idle"`    // 空闲时长（秒）
+	Db      int    `json:"db"`      // 当前库
+	Cmd     string `json:"cmd"`     // 最近执行的命令
+	Flags   string `json:"flags"`   // 客户端标志，如N普通、S从库、M主库、P订阅
+	Omem    int64  `json:"omem"`    // 输出缓冲区占用（字节）
+	TotMem  int64  `json:"totMem"`  // 连接占用的总内存（字节）
+	Qbuf    int64  `json:"qbuf"`    // 查询缓冲区长度（字节）
+	Sub     int    `json:"sub"`     // 订阅的频道数量
+	Psub    int    `json:"psub"`    // 订阅的模式数量
+	Multi   int    `json:"multi"`   // 事务中的命令数量，-1表示不在事务中
+	User    string `json:"user"`    // ACL用户名（6.0+）
+	Resp    int    `json:"resp"`    // 协议版本（7.0+）
+	LibName string `json:"libName"` // 客户端库名称（7.2+）
+	LibVer  string `json:"libVer"`  // 客户端库版本（7.2+）
+}
+
+// Redis客户端分组统计
+type RedisClientGroup struct {
+	Key       string           `json:"key"`       // 分组值（客户端名称或IP），空字符串表示未设置名称
+	Count     int64            `json:"count"`     // 连接数量
+	AvgIdle   int64            `json:"avgIdle"`   // 平均空闲时长（秒）
+	MaxIdle   int64            `json:"maxIdle"`   // 最大空闲时长（秒）
+	MaxAge    int64            `json:"maxAge"`    // 最长连接时长（秒）
+	TotalOmem int64            `json:"totalOmem"` // 输出缓冲区总占用（字节）
+	TotalMem  int64            `json:"totalMem"`  // 连接占用的总内存（字节）
+	Commands  map[string]int64 `json:"commands"`  // 最近执行命令的分布
+}
+
+// Redis客户端列表响应VO
+type RedisClientListResponse struct {
+	Clients []RedisClientInfo  `json:"clients"` // 客户端连接，按空闲时长倒序
+	Total   int                `json:"total"`   // 连接总数
+	ByName  []RedisClientGroup `json:"byName"`  // 按客户端名称分组，按连接数倒序
+	ByIp    []RedisClientGroup `json:"byIp"`    // 按来源IP分组，按连接数倒序
+}
+
+// Redis断开客户端连接响应VO
+type RedisClientKillResponse struct {
+	Killed int64 `json:"killed"` // 断开的连接数量
+}
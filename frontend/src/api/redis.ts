@@ -63,7 +63,34 @@ export function setRedisKey(data: any) {
   })
 }
 
-// 搜索Redis Keys (后端搜索，使用SCAN + strings.Contains)
+// 重命名Redis Key（RENAMENX，overwrite为true时RENAME）
+export function renameRedisKey(data: any) {
+  return request({
+    url: '/api/RedisRenameKey',
+    method: 'post',
+    data
+  })
+}
+
+// 复制Redis Key（COPY，低版本退化为DUMP/RESTORE）
+export function copyRedisKey(data: any) {
+  return request({
+    url: '/api/RedisCopyKey',
+    method: 'post',
+    data
+  })
+}
+
+// 移动Redis Key到其他库（MOVE）
+export function moveRedisKey(data: any) {
+  return request({
+    url: '/api/RedisMoveKey',
+    method: 'post',
+    data
+  })
+}
+
+// 搜索Redis Keys (后端按游标分页搜索，支持contains/glob/prefix/regex匹配模式)
 export function searchRedisKeys(data: any) {
   return request({
     url: '/api/RedisSearchKeys',
@@ -89,3 +116,432 @@ export function getRedisBatchMemoryAnalysis(data: any) {
     data
   })
 }
+
+// 启动Redis后台任务（只读任务，如全量内存分析）
+export function startRedisJob(data: any) {
+  return request({
+    url: '/api/RedisJobStart',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis后台任务状态
+export function getRedisJobStatus(data: any) {
+  return request({
+    url: '/api/RedisJobStatus',
+    method: 'post',
+    data
+  })
+}
+
+// 取消Redis后台任务
+export function cancelRedisJob(data: any) {
+  return request({
+    url: '/api/RedisJobCancel',
+    method: 'post',
+    data
+  })
+}
+
+// 从断点继续已中断、失败或取消的Redis后台任务
+export function resumeRedisJob(data: any) {
+  return request({
+    url: '/api/RedisJobResume',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis后台任务结果
+export function getRedisJobResult(data: any) {
+  return request({
+    url: '/api/RedisJobResult',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis后台任务列表
+export function getRedisJobList(data: any) {
+  return request({
+    url: '/api/RedisJobList',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis Key命名空间树（按分隔符聚合，按前缀懒加载）
+export function getRedisNamespaceTree(data: any) {
+  return request({
+    url: '/api/RedisNamespaceTree',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis大Key分析（按游标分页）
+export function getRedisBigKeys(data: any) {
+  return request({
+    url: '/api/RedisBigKeys',
+    method: 'post',
+    data
+  })
+}
+
+// 导出Redis大Key分析任务结果（CSV）
+export function exportRedisBigKeys(data: any) {
+  return request({
+    url: '/api/RedisBigKeysExport',
+    method: 'post',
+    data,
+    responseType: 'blob'
+  })
+}
+
+// 导出Redis Key为JSON Lines文件（key、type、ttl、value），最后一行为汇总行（summary、complete、failedKeys、error）
+export function exportRedisKeys(data: any) {
+  return request({
+    url: '/api/RedisExportKeys',
+    method: 'post',
+    data,
+    responseType: 'blob'
+  })
+}
+
+// 导入JSON Lines文件（dry_run时只校验并统计冲突，否则启动后台任务）
+export function importRedisKeys(data: any) {
+  return request({
+    url: '/api/RedisImportKeys',
+    method: 'post',
+    data
+  })
+}
+
+// 导入RESP或redis-cli命令文件（后台任务）
+export function importRedisCommands(data: any) {
+  return request({
+    url: '/api/RedisImportCommands',
+    method: 'post',
+    data
+  })
+}
+
+// 创建Key快照（后台任务）
+// 序列化值需在Redis脚本中编码，执行期间会阻塞Redis，包含MEMORY USAGE超过4MB的Key时快照失败
+export function createRedisSnapshot(data: any) {
+  return request({
+    url: '/api/RedisSnapshotCreate',
+    method: 'post',
+    data
+  })
+}
+
+// 从快照恢复Key（后台任务，RESTORE REPLACE）
+export function restoreRedisSnapshot(data: any) {
+  return request({
+    url: '/api/RedisSnapshotRestore',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Key快照
+export function deleteRedisSnapshot(data: any) {
+  return request({
+    url: '/api/RedisSnapshotDelete',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Key快照列表
+export function getRedisSnapshotList(data: any) {
+  return request({
+    url: '/api/RedisSnapshotList',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Key快照详情
+export function getRedisSnapshotDetail(data: any) {
+  return request({
+    url: '/api/RedisSnapshotDetail',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis热Key分析（OBJECT FREQ / 上传的MONITOR输出解析 / 显式指定的OBJECT IDLETIME），插件无法在线执行MONITOR
+export function getRedisHotKeys(data: any) {
+  return request({
+    url: '/api/RedisHotKeys',
+    method: 'post',
+    data
+  })
+}
+
+// 获取Redis Key过期时间分布分析（按游标分页扫描或RANDOMKEY采样）
+export function getRedisTtlAnalysis(data: any) {
+  return request({
+    url: '/api/RedisTtlAnalysis',
+    method: 'post',
+    data
+  })
+}
+
+// 获取慢日志及按命令与key模式的聚合
+export function getRedisSlowlog(data: any) {
+  return request({
+    url: '/api/RedisSlowlog',
+    method: 'post',
+    data
+  })
+}
+
+// 获取慢日志条数
+export function getRedisSlowlogLen(data: any) {
+  return request({
+    url: '/api/RedisSlowlogLen',
+    method: 'post',
+    data
+  })
+}
+
+// 清空慢日志
+export function resetRedisSlowlog(data: any) {
+  return request({
+    url: '/api/RedisSlowlogReset',
+    method: 'post',
+    data
+  })
+}
+
+// 获取客户端连接列表及按名称、IP的分组
+export function getRedisClientList(data: any) {
+  return request({
+    url: '/api/RedisClientList',
+    method: 'post',
+    data
+  })
+}
+
+// 断开客户端连接（id、addr、user三选一）
+export function killRedisClient(data: any) {
+  return request({
+    url: '/api/RedisClientKill',
+    method: 'post',
+    data
+  })
+}
+
+// 暂停客户端
+export function pauseRedisClients(data: any) {
+  return request({
+    url: '/api/RedisClientPause',
+    method: 'post',
+    data
+  })
+}
+
+// 恢复客户端
+export function unpauseRedisClients(data: any) {
+  return request({
+    url: '/api/RedisClientUnpause',
+    method: 'post',
+    data
+  })
+}
+
+// 离线分析RDB文件（后台任务），data为FormData（file与params）或包含path的对象
+export function analyzeRedisRdb(data: any) {
+  return request({
+    url: '/api/RedisRdbAnalysis',
+    method: 'post',
+    data
+  })
+}
+
+// 对比两个数据源或库的keyspace差异（后台任务）
+// MEMORY USAGE超过4MB的Key不计算值摘要，类型与TTL一致时计入unverified，不判断值是否一致
+export function diffRedisKeyspace(data: any) {
+  return request({
+    url: '/api/RedisKeyspaceDiff',
+    method: 'post',
+    data
+  })
+}
+
+// 向Redis Stream追加消息（XADD）
+export function addRedisStreamEntry(data: any) {
+  return request({
+    url: '/api/RedisStreamAdd',
+    method: 'post',
+    data
+  })
+}
+
+// 裁剪Redis Stream（XTRIM MAXLEN/MINID）
+export function trimRedisStream(data: any) {
+  return request({
+    url: '/api/RedisStreamTrim',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis Stream消息（XDEL）
+export function deleteRedisStreamEntries(data: any) {
+  return request({
+    url: '/api/RedisStreamDelete',
+    method: 'post',
+    data
+  })
+}
+
+// 创建Redis Stream消费组（XGROUP CREATE）
+export function createRedisStreamGroup(data: any) {
+  return request({
+    url: '/api/RedisStreamGroupCreate',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis Stream消费组（XGROUP DESTROY）
+export function destroyRedisStreamGroup(data: any) {
+  return request({
+    url: '/api/RedisStreamGroupDestroy',
+    method: 'post',
+    data
+  })
+}
+
+// 设置Redis Hash字段（HSET / HSETNX）
+export function setRedisHashField(data: any) {
+  return request({
+    url: '/api/RedisHashSet',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis Hash字段（HDEL）
+export function deleteRedisHashFields(data: any) {
+  return request({
+    url: '/api/RedisHashDelete',
+    method: 'post',
+    data
+  })
+}
+
+// 按下标修改Redis List元素（LSET）
+export function setRedisListItem(data: any) {
+  return request({
+    url: '/api/RedisListSet',
+    method: 'post',
+    data
+  })
+}
+
+// 插入Redis List元素（LINSERT / LPUSH / RPUSH）
+export function insertRedisListItem(data: any) {
+  return request({
+    url: '/api/RedisListInsert',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis List元素（LREM，指定index时只删除该下标上的元素）
+export function removeRedisListItem(data: any) {
+  return request({
+    url: '/api/RedisListRemove',
+    method: 'post',
+    data
+  })
+}
+
+// 添加Redis Set成员（SADD）
+export function addRedisSetMembers(data: any) {
+  return request({
+    url: '/api/RedisSetAdd',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis Set成员（SREM）
+export function removeRedisSetMembers(data: any) {
+  return request({
+    url: '/api/RedisSetRemove',
+    method: 'post',
+    data
+  })
+}
+
+// 添加或更新Redis ZSet成员（ZADD）
+export function addRedisZSetMembers(data: any) {
+  return request({
+    url: '/api/RedisZSetAdd',
+    method: 'post',
+    data
+  })
+}
+
+// 增加Redis ZSet成员分数（ZINCRBY）
+export function incrRedisZSetScore(data: any) {
+  return request({
+    url: '/api/RedisZSetIncrBy',
+    method: 'post',
+    data
+  })
+}
+
+// 删除Redis ZSet成员（ZREM）
+export function removeRedisZSetMembers(data: any) {
+  return request({
+    url: '/api/RedisZSetRemove',
+    method: 'post',
+    data
+  })
+}
+
+// 预览按模式批量删除Redis Key（只扫描，返回匹配数量与样例）
+export function previewRedisBulkDelete(data: any) {
+  return request({
+    url: '/api/RedisBulkDeletePreview',
+    method: 'post',
+    data
+  })
+}
+
+// 按模式批量删除Redis Key（后台任务，分批UNLINK并限速，返回任务信息）
+// 匹配所有Key的模式（如*、.*）需传confirm_all: true；传入预览的expected时，匹配数量远超预览数量会停止任务
+export function bulkDeleteRedisKeys(data: any) {
+  return request({
+    url: '/api/RedisBulkDelete',
+    method: 'post',
+    data
+  })
+}
+
+// 批量设置Redis Key过期时间（EXPIRE/PEXPIRE/EXPIREAT/PERSIST），指定keys时同步返回结果，按模式执行时返回后台任务
+export function bulkExpireRedisKeys(data: any) {
+  return request({
+    url: '/api/RedisBulkExpire',
+    method: 'post',
+    data
+  })
+}
+
+// 跨数据源迁移Redis Key（后台任务，DUMP/RESTORE保留TTL）
+// 序列化值需在Redis脚本中编码，执行期间会阻塞Redis，MEMORY USAGE超过4MB的Key会被跳过并记为失败，需改用其他工具迁移
+export function migrateRedisKeys(data: any) {
+  return request({
+    url: '/api/RedisMigrateKeys',
+    method: 'post',
+    data
+  })
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	_ "embed"
+	"ev-plugin/backend/migrate"
 	"ev-plugin/backend/router"
 	"ev-plugin/frontend"
 	"flag"
@@ -30,7 +31,10 @@ func main() {
 		ReadyCallBack: func(ctx context.Context) {
 
 		},
-		Migration:      &build.Gormigrate{Migrations: []*build.Migration{}}, //数据版本迁移
+		Migration: &build.Gormigrate{Migrations: []*build.Migration{
+			migrate.V0_0_2(),
+			migrate.V0_0_3(),
+		}}, //数据版本迁移
 		RegisterRoutes: router.NewRouter,
 	})
 }